@@ -0,0 +1,52 @@
+package cc1101
+
+import "fmt"
+
+// SetAddress sets the device address compared against the first payload
+// byte of received packets when address checking is enabled.
+func (d *Device) SetAddress(addr byte) error {
+	return d.WriteSingleRegister(ADDR, addr)
+}
+
+// GetAddress reads the device address.
+func (d *Device) GetAddress() (byte, error) {
+	return d.ReadSingleRegister(ADDR)
+}
+
+// SetAddressCheck selects how the radio filters received packets on their
+// address byte. Packets that fail the check are discarded by the chip and
+// never reach the RX FIFO.
+func (d *Device) SetAddressCheck(mode AddressCheck) error {
+	var p Pktctrl1
+	return d.modify(&p, func() { p.AddressCheck = mode & 0x03 })
+}
+
+// GetAddressCheck reads the address check mode.
+func (d *Device) GetAddressCheck() (AddressCheck, error) {
+	var p Pktctrl1
+	if err := d.ReadRegister(&p); err != nil {
+		return 0, err
+	}
+	return p.AddressCheck, nil
+}
+
+// SendDataTo sends payload to the device with address addr. The address is
+// sent as the first byte after the length byte, where the receiver's packet
+// handler expects it, which leaves room for 62 payload bytes in the FIFO.
+func (d *Device) SendDataTo(addr byte, payload []byte) error {
+	if len(payload) > FIFOBUFFER-2 {
+		return fmt.Errorf("payload too long: %d bytes (max %d with the length and address bytes)", len(payload), FIFOBUFFER-2)
+	}
+	d.txMu.Lock()
+	defer d.txMu.Unlock()
+	packet := d.txFIFO[:1+len(payload)]
+	copy(packet[1:], payload)
+	packet[0] = addr
+	return d.sendData(packet)
+}
+
+// SendBroadcast sends payload to BROADCAST_ADDRESS, accepted by receivers in
+// AddrCheckBroadcast and AddrCheckBroadcastBoth mode.
+func (d *Device) SendBroadcast(payload []byte) error {
+	return d.SendDataTo(BROADCAST_ADDRESS, payload)
+}
@@ -0,0 +1,26 @@
+package cc1101
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSendDataTo(t *testing.T) {
+	d, chip := newFakeDevice()
+	payload := bytes.Repeat([]byte{0xA5}, FIFOBUFFER-2)
+	if err := d.SendDataTo(0x42, payload); err != nil {
+		t.Fatal(err)
+	}
+	want := append([]byte{FIFOBUFFER - 1, 0x42}, payload...)
+	if sent := chip.packets(); len(sent) != 1 || !bytes.Equal(sent[0], want) {
+		t.Errorf("TX FIFO = % X, want % X", sent, want)
+	}
+
+	err := d.SendDataTo(0x42, append(payload, 0xA5))
+	if err == nil {
+		t.Fatal("SendDataTo accepted a 63-byte payload")
+	}
+	if len(chip.packets()) != 1 {
+		t.Error("SendDataTo sent a packet too long for the FIFO")
+	}
+}
@@ -0,0 +1,946 @@
+package cc1101
+
+import "fmt"
+
+// Register is implemented by the typed view of every configuration register.
+// Encode packs the named fields into the register byte and Decode does the
+// reverse, reporting reserved bits that are set or field values the chip
+// does not define.
+// Read page 66 https://www.ti.com/lit/ds/symlink/cc1101.pdf
+type Register interface {
+	Addr() byte
+	Encode() byte
+	Decode(value byte) error
+}
+
+// ReservedBitsError is returned by Decode when a value has bits set that the
+// datasheet marks as reserved.
+type ReservedBitsError struct {
+	Addr  byte
+	Value byte
+	Mask  byte
+}
+
+func (e *ReservedBitsError) Error() string {
+	return fmt.Sprintf("register 0x%02X: reserved bits 0x%02X set in 0x%02X", e.Addr, e.Value&e.Mask, e.Value)
+}
+
+func checkReserved(addr, value, mask byte) error {
+	if value&mask != 0 {
+		return &ReservedBitsError{Addr: addr, Value: value, Mask: mask}
+	}
+	return nil
+}
+
+func bit(v bool, n uint) byte {
+	if v {
+		return 1 << n
+	}
+	return 0
+}
+
+// Reset values of the configuration registers 0x00-0x2E.
+var resetDefaults = [CFG_REGISTER]byte{
+	0x29, 0x2E, 0x3F, 0x07, 0xD3, 0x91, 0xFF, 0x04, // IOCFG2 .. PKTCTRL1
+	0x45, 0x00, 0x00, 0x0F, 0x00, 0x1E, 0xC4, 0xEC, // PKTCTRL0 .. FREQ0
+	0x8C, 0x22, 0x02, 0x22, 0xF8, 0x47, 0x07, 0x30, // MDMCFG4 .. MCSM1
+	0x04, 0x36, 0x6C, 0x03, 0x40, 0x91, 0x87, 0x6B, // MCSM0 .. WOREVT0
+	0xF8, 0x56, 0x10, 0xA9, 0x0A, 0x20, 0x0D, 0x41, // WORCTRL .. RCCTRL1
+	0x00, 0x59, 0x7F, 0x3F, 0x88, 0x31, 0x0B, // RCCTRL0 .. TEST0
+}
+
+// Modulation is the MDMCFG2 MOD_FORMAT field.
+type Modulation byte
+
+const (
+	Modulation2FSK Modulation = 0x00
+	ModulationGFSK Modulation = 0x01
+	ModulationOOK  Modulation = 0x03 // ASK/OOK
+	Modulation4FSK Modulation = 0x04
+	ModulationMSK  Modulation = 0x07
+)
+
+func (m Modulation) String() string {
+	switch m {
+	case Modulation2FSK:
+		return "2FSK"
+	case ModulationGFSK:
+		return "GFSK"
+	case ModulationOOK:
+		return "OOK"
+	case Modulation4FSK:
+		return "4FSK"
+	case ModulationMSK:
+		return "MSK"
+	}
+	return fmt.Sprintf("Modulation(%d)", byte(m))
+}
+
+// ParseModulation accepts the names returned by Modulation.String.
+func ParseModulation(s string) (Modulation, error) {
+	for _, m := range []Modulation{Modulation2FSK, ModulationGFSK, ModulationOOK, Modulation4FSK, ModulationMSK} {
+		if m.String() == s {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported modulation type: %q", s)
+}
+
+// SyncMode is the MDMCFG2 SYNC_MODE field.
+type SyncMode byte
+
+const (
+	SyncNone       SyncMode = 0x00 // No preamble/sync
+	Sync15of16     SyncMode = 0x01 // 15/16 sync word bits detected
+	Sync16of16     SyncMode = 0x02 // 16/16 sync word bits detected
+	Sync30of32     SyncMode = 0x03 // 30/32 sync word bits detected
+	SyncNoneCS     SyncMode = 0x04 // No preamble/sync, carrier-sense above threshold
+	Sync15of16CS   SyncMode = 0x05 // 15/16 + carrier-sense above threshold
+	Sync16of16CS   SyncMode = 0x06 // 16/16 + carrier-sense above threshold
+	Sync30of32CS   SyncMode = 0x07 // 30/32 + carrier-sense above threshold
+	syncModeCSFlag SyncMode = 0x04
+)
+
+// LengthConfig is the PKTCTRL0 LENGTH_CONFIG field.
+type LengthConfig byte
+
+const (
+	LengthFixed    LengthConfig = 0x00 // Length set by PKTLEN
+	LengthVariable LengthConfig = 0x01 // Length given by the first byte after sync
+	LengthInfinite LengthConfig = 0x02
+)
+
+// PacketFormat is the PKTCTRL0 PKT_FORMAT field.
+type PacketFormat byte
+
+const (
+	FormatNormal      PacketFormat = 0x00 // Use FIFOs for RX and TX
+	FormatSyncSerial  PacketFormat = 0x01 // Synchronous serial mode, data in on GDO0
+	FormatRandomTX    PacketFormat = 0x02 // Random TX mode, PN9 generator
+	FormatAsyncSerial PacketFormat = 0x03 // Asynchronous serial mode, data in on GDO0
+)
+
+// AddressCheck is the PKTCTRL1 ADR_CHK field.
+type AddressCheck byte
+
+const (
+	AddrCheckNone          AddressCheck = 0x00
+	AddrCheckExact         AddressCheck = 0x01 // No broadcast
+	AddrCheckBroadcast     AddressCheck = 0x02 // Address and 0x00 broadcast
+	AddrCheckBroadcastBoth AddressCheck = 0x03 // Address, 0x00 and 0xFF broadcast
+)
+
+// OffMode is the MCSM1 RXOFF_MODE / TXOFF_MODE field: the state entered
+// once a packet has been received or sent.
+type OffMode byte
+
+const (
+	OffModeIdle   OffMode = 0x00
+	OffModeFSTXON OffMode = 0x01
+	OffModeTX     OffMode = 0x02
+	OffModeRX     OffMode = 0x03
+)
+
+// CCAMode is the MCSM1 CCA_MODE field.
+type CCAMode byte
+
+const (
+	CCAAlways        CCAMode = 0x00
+	CCARSSI          CCAMode = 0x01 // If RSSI below threshold
+	CCAPacket        CCAMode = 0x02 // Unless currently receiving a packet
+	CCARSSIAndPacket CCAMode = 0x03
+)
+
+// AutoCal is the MCSM0 FS_AUTOCAL field.
+type AutoCal byte
+
+const (
+	AutoCalNever    AutoCal = 0x00 // Manually using SCAL
+	AutoCalFromIdle AutoCal = 0x01 // When going from IDLE to RX or TX
+	AutoCalToIdle   AutoCal = 0x02 // When going from RX or TX back to IDLE
+	AutoCalEvery4th AutoCal = 0x03 // Every 4th time when going from RX or TX to IDLE
+)
+
+// Iocfg2 : 0x00 GDO2 output pin configuration
+type Iocfg2 struct {
+	Invert bool // GDO2_INV
+	Config byte // GDO2_CFG, 6 bits
+}
+
+func (r *Iocfg2) Addr() byte { return IOCFG2 }
+func (r *Iocfg2) Encode() byte {
+	return bit(r.Invert, 6) | r.Config&0x3F
+}
+func (r *Iocfg2) Decode(v byte) error {
+	r.Invert = v&0x40 != 0
+	r.Config = v & 0x3F
+	return checkReserved(IOCFG2, v, 0x80)
+}
+func (r *Iocfg2) fields() []field {
+	return []field{{"GDO2_INV", boolValue(r.Invert)}, {"GDO2_CFG", int(r.Config)}}
+}
+
+// Iocfg1 : 0x01 GDO1 output pin configuration
+type Iocfg1 struct {
+	DriveStrength bool // GDO_DS, high drive strength on all GDO pins
+	Invert        bool // GDO1_INV
+	Config        byte // GDO1_CFG, 6 bits
+}
+
+func (r *Iocfg1) Addr() byte { return IOCFG1 }
+func (r *Iocfg1) Encode() byte {
+	return bit(r.DriveStrength, 7) | bit(r.Invert, 6) | r.Config&0x3F
+}
+func (r *Iocfg1) Decode(v byte) error {
+	r.DriveStrength = v&0x80 != 0
+	r.Invert = v&0x40 != 0
+	r.Config = v & 0x3F
+	return nil
+}
+func (r *Iocfg1) fields() []field {
+	return []field{{"GDO_DS", boolValue(r.DriveStrength)}, {"GDO1_INV", boolValue(r.Invert)}, {"GDO1_CFG", int(r.Config)}}
+}
+
+// Iocfg0 : 0x02 GDO0 output pin configuration
+type Iocfg0 struct {
+	TempSensor bool // TEMP_SENSOR_ENABLE
+	Invert     bool // GDO0_INV
+	Config     byte // GDO0_CFG, 6 bits
+}
+
+func (r *Iocfg0) Addr() byte { return IOCFG0 }
+func (r *Iocfg0) Encode() byte {
+	return bit(r.TempSensor, 7) | bit(r.Invert, 6) | r.Config&0x3F
+}
+func (r *Iocfg0) Decode(v byte) error {
+	r.TempSensor = v&0x80 != 0
+	r.Invert = v&0x40 != 0
+	r.Config = v & 0x3F
+	return nil
+}
+func (r *Iocfg0) fields() []field {
+	return []field{{"TEMP_SENSOR_ENABLE", boolValue(r.TempSensor)}, {"GDO0_INV", boolValue(r.Invert)}, {"GDO0_CFG", int(r.Config)}}
+}
+
+// Fifothr : 0x03 RX FIFO and TX FIFO thresholds
+type Fifothr struct {
+	ADCRetention bool // ADC_RETENTION
+	CloseInRX    byte // CLOSE_IN_RX, 2 bits
+	Threshold    byte // FIFO_THR, 4 bits
+}
+
+func (r *Fifothr) Addr() byte { return FIFOTHR }
+func (r *Fifothr) Encode() byte {
+	return bit(r.ADCRetention, 6) | (r.CloseInRX&0x03)<<4 | r.Threshold&0x0F
+}
+func (r *Fifothr) Decode(v byte) error {
+	r.ADCRetention = v&0x40 != 0
+	r.CloseInRX = (v >> 4) & 0x03
+	r.Threshold = v & 0x0F
+	return checkReserved(FIFOTHR, v, 0x80)
+}
+func (r *Fifothr) fields() []field {
+	return []field{{"ADC_RETENTION", boolValue(r.ADCRetention)}, {"CLOSE_IN_RX", int(r.CloseInRX)}, {"FIFO_THR", int(r.Threshold)}}
+}
+
+// Pktctrl1 : 0x07 Packet automation control
+type Pktctrl1 struct {
+	PQT          byte         // PQT, 3 bits
+	CRCAutoflush bool         // CRC_AUTOFLUSH
+	AppendStatus bool         // APPEND_STATUS
+	AddressCheck AddressCheck // ADR_CHK
+}
+
+func (r *Pktctrl1) Addr() byte { return PKTCTRL1 }
+func (r *Pktctrl1) Encode() byte {
+	return (r.PQT&0x07)<<5 | bit(r.CRCAutoflush, 3) | bit(r.AppendStatus, 2) | byte(r.AddressCheck)&0x03
+}
+func (r *Pktctrl1) Decode(v byte) error {
+	r.PQT = v >> 5
+	r.CRCAutoflush = v&0x08 != 0
+	r.AppendStatus = v&0x04 != 0
+	r.AddressCheck = AddressCheck(v & 0x03)
+	return checkReserved(PKTCTRL1, v, 0x10)
+}
+func (r *Pktctrl1) fields() []field {
+	return []field{{"PQT", int(r.PQT)}, {"CRC_AUTOFLUSH", boolValue(r.CRCAutoflush)}, {"APPEND_STATUS", boolValue(r.AppendStatus)}, {"ADR_CHK", int(r.AddressCheck)}}
+}
+
+// Pktctrl0 : 0x08 Packet automation control
+type Pktctrl0 struct {
+	Whitening bool         // WHITE_DATA
+	Format    PacketFormat // PKT_FORMAT
+	CRC       bool         // CRC_EN
+	Length    LengthConfig // LENGTH_CONFIG
+}
+
+func (r *Pktctrl0) Addr() byte { return PKTCTRL0 }
+func (r *Pktctrl0) Encode() byte {
+	return bit(r.Whitening, 6) | (byte(r.Format)&0x03)<<4 | bit(r.CRC, 2) | byte(r.Length)&0x03
+}
+func (r *Pktctrl0) Decode(v byte) error {
+	r.Whitening = v&0x40 != 0
+	r.Format = PacketFormat((v >> 4) & 0x03)
+	r.CRC = v&0x04 != 0
+	r.Length = LengthConfig(v & 0x03)
+	if r.Length > LengthInfinite {
+		return fmt.Errorf("register 0x%02X: invalid LENGTH_CONFIG %d", PKTCTRL0, r.Length)
+	}
+	return checkReserved(PKTCTRL0, v, 0x88)
+}
+func (r *Pktctrl0) fields() []field {
+	return []field{{"WHITE_DATA", boolValue(r.Whitening)}, {"PKT_FORMAT", int(r.Format)}, {"CRC_EN", boolValue(r.CRC)}, {"LENGTH_CONFIG", int(r.Length)}}
+}
+
+// Fsctrl1 : 0x0B Frequency synthesizer control
+type Fsctrl1 struct {
+	FreqIF byte // FREQ_IF, 5 bits
+}
+
+func (r *Fsctrl1) Addr() byte   { return FSCTRL1 }
+func (r *Fsctrl1) Encode() byte { return r.FreqIF & 0x1F }
+func (r *Fsctrl1) Decode(v byte) error {
+	r.FreqIF = v & 0x1F
+	return checkReserved(FSCTRL1, v, 0xE0)
+}
+func (r *Fsctrl1) fields() []field {
+	return []field{{"FREQ_IF", int(r.FreqIF)}}
+}
+
+// Freq2 : 0x0D Frequency control word, high byte
+type Freq2 struct {
+	Freq byte // FREQ[21:16], 6 bits; FREQ[23:22] are always 0
+}
+
+func (r *Freq2) Addr() byte   { return FREQ2 }
+func (r *Freq2) Encode() byte { return r.Freq & 0x3F }
+func (r *Freq2) Decode(v byte) error {
+	r.Freq = v & 0x3F
+	return checkReserved(FREQ2, v, 0xC0)
+}
+func (r *Freq2) fields() []field {
+	return []field{{"FREQ[21:16]", int(r.Freq)}}
+}
+
+// Mdmcfg4 : 0x10 Modem configuration
+type Mdmcfg4 struct {
+	ChanBwE byte // CHANBW_E, 2 bits
+	ChanBwM byte // CHANBW_M, 2 bits
+	DrateE  byte // DRATE_E, 4 bits
+}
+
+func (r *Mdmcfg4) Addr() byte { return MDMCFG4 }
+func (r *Mdmcfg4) Encode() byte {
+	return (r.ChanBwE&0x03)<<6 | (r.ChanBwM&0x03)<<4 | r.DrateE&0x0F
+}
+func (r *Mdmcfg4) Decode(v byte) error {
+	r.ChanBwE = v >> 6
+	r.ChanBwM = (v >> 4) & 0x03
+	r.DrateE = v & 0x0F
+	return nil
+}
+func (r *Mdmcfg4) fields() []field {
+	return []field{{"CHANBW_E", int(r.ChanBwE)}, {"CHANBW_M", int(r.ChanBwM)}, {"DRATE_E", int(r.DrateE)}}
+}
+
+// Mdmcfg3 : 0x11 Modem configuration
+type Mdmcfg3 struct {
+	DrateM byte // DRATE_M
+}
+
+func (r *Mdmcfg3) Addr() byte          { return MDMCFG3 }
+func (r *Mdmcfg3) Encode() byte        { return r.DrateM }
+func (r *Mdmcfg3) Decode(v byte) error { r.DrateM = v; return nil }
+func (r *Mdmcfg3) fields() []field {
+	return []field{{"DRATE_M", int(r.DrateM)}}
+}
+
+// Mdmcfg2 : 0x12 Modem configuration
+// | DCOFF   | MODFM    | MANCH   | SYNCM    |
+// | 7th bit | 6-4 bits | 3rd bit | 2-0 bits |
+type Mdmcfg2 struct {
+	DCFilterOff bool       // DEM_DCFILT_OFF
+	Modulation  Modulation // MOD_FORMAT
+	Manchester  bool       // MANCHESTER_EN
+	SyncMode    SyncMode   // SYNC_MODE
+}
+
+func (r *Mdmcfg2) Addr() byte { return MDMCFG2 }
+func (r *Mdmcfg2) Encode() byte {
+	return bit(r.DCFilterOff, 7) | (byte(r.Modulation)&0x07)<<4 | bit(r.Manchester, 3) | byte(r.SyncMode)&0x07
+}
+func (r *Mdmcfg2) Decode(v byte) error {
+	r.DCFilterOff = v&0x80 != 0
+	r.Modulation = Modulation((v >> 4) & 0x07)
+	r.Manchester = v&0x08 != 0
+	r.SyncMode = SyncMode(v & 0x07)
+	switch r.Modulation {
+	case Modulation2FSK, ModulationGFSK, ModulationOOK, Modulation4FSK, ModulationMSK:
+		return nil
+	}
+	return fmt.Errorf("register 0x%02X: invalid MOD_FORMAT %d", MDMCFG2, byte(r.Modulation))
+}
+func (r *Mdmcfg2) fields() []field {
+	return []field{{"DEM_DCFILT_OFF", boolValue(r.DCFilterOff)}, {"MOD_FORMAT", int(r.Modulation)}, {"MANCHESTER_EN", boolValue(r.Manchester)}, {"SYNC_MODE", int(r.SyncMode)}}
+}
+
+// Mdmcfg1 : 0x13 Modem configuration
+type Mdmcfg1 struct {
+	FEC         bool // FEC_EN
+	NumPreamble byte // NUM_PREAMBLE, 3 bits
+	ChanSpcE    byte // CHANSPC_E, 2 bits
+}
+
+func (r *Mdmcfg1) Addr() byte { return MDMCFG1 }
+func (r *Mdmcfg1) Encode() byte {
+	return bit(r.FEC, 7) | (r.NumPreamble&0x07)<<4 | r.ChanSpcE&0x03
+}
+func (r *Mdmcfg1) Decode(v byte) error {
+	r.FEC = v&0x80 != 0
+	r.NumPreamble = (v >> 4) & 0x07
+	r.ChanSpcE = v & 0x03
+	return checkReserved(MDMCFG1, v, 0x0C)
+}
+func (r *Mdmcfg1) fields() []field {
+	return []field{{"FEC_EN", boolValue(r.FEC)}, {"NUM_PREAMBLE", int(r.NumPreamble)}, {"CHANSPC_E", int(r.ChanSpcE)}}
+}
+
+// Mdmcfg0 : 0x14 Modem configuration
+type Mdmcfg0 struct {
+	ChanSpcM byte // CHANSPC_M
+}
+
+func (r *Mdmcfg0) Addr() byte          { return MDMCFG0 }
+func (r *Mdmcfg0) Encode() byte        { return r.ChanSpcM }
+func (r *Mdmcfg0) Decode(v byte) error { r.ChanSpcM = v; return nil }
+func (r *Mdmcfg0) fields() []field {
+	return []field{{"CHANSPC_M", int(r.ChanSpcM)}}
+}
+
+// Deviatn : 0x15 Modem deviation setting
+type Deviatn struct {
+	DeviationE byte // DEVIATION_E, 3 bits
+	DeviationM byte // DEVIATION_M, 3 bits
+}
+
+func (r *Deviatn) Addr() byte { return DEVIATN }
+func (r *Deviatn) Encode() byte {
+	return (r.DeviationE&0x07)<<4 | r.DeviationM&0x07
+}
+func (r *Deviatn) Decode(v byte) error {
+	r.DeviationE = (v >> 4) & 0x07
+	r.DeviationM = v & 0x07
+	return checkReserved(DEVIATN, v, 0x88)
+}
+func (r *Deviatn) fields() []field {
+	return []field{{"DEVIATION_E", int(r.DeviationE)}, {"DEVIATION_M", int(r.DeviationM)}}
+}
+
+// Mcsm2 : 0x16 Main Radio Control State Machine configuration
+type Mcsm2 struct {
+	RxTimeRSSI bool // RX_TIME_RSSI
+	RxTimeQual bool // RX_TIME_QUAL
+	RxTime     byte // RX_TIME, 3 bits
+}
+
+func (r *Mcsm2) Addr() byte { return MCSM2 }
+func (r *Mcsm2) Encode() byte {
+	return bit(r.RxTimeRSSI, 4) | bit(r.RxTimeQual, 3) | r.RxTime&0x07
+}
+func (r *Mcsm2) Decode(v byte) error {
+	r.RxTimeRSSI = v&0x10 != 0
+	r.RxTimeQual = v&0x08 != 0
+	r.RxTime = v & 0x07
+	return checkReserved(MCSM2, v, 0xE0)
+}
+func (r *Mcsm2) fields() []field {
+	return []field{{"RX_TIME_RSSI", boolValue(r.RxTimeRSSI)}, {"RX_TIME_QUAL", boolValue(r.RxTimeQual)}, {"RX_TIME", int(r.RxTime)}}
+}
+
+// Mcsm1 : 0x17 Main Radio Control State Machine configuration
+type Mcsm1 struct {
+	CCAMode   CCAMode // CCA_MODE
+	RxOffMode OffMode // RXOFF_MODE
+	TxOffMode OffMode // TXOFF_MODE
+}
+
+func (r *Mcsm1) Addr() byte { return MCSM1 }
+func (r *Mcsm1) Encode() byte {
+	return (byte(r.CCAMode)&0x03)<<4 | (byte(r.RxOffMode)&0x03)<<2 | byte(r.TxOffMode)&0x03
+}
+func (r *Mcsm1) Decode(v byte) error {
+	r.CCAMode = CCAMode((v >> 4) & 0x03)
+	r.RxOffMode = OffMode((v >> 2) & 0x03)
+	r.TxOffMode = OffMode(v & 0x03)
+	return checkReserved(MCSM1, v, 0xC0)
+}
+func (r *Mcsm1) fields() []field {
+	return []field{{"CCA_MODE", int(r.CCAMode)}, {"RXOFF_MODE", int(r.RxOffMode)}, {"TXOFF_MODE", int(r.TxOffMode)}}
+}
+
+// Mcsm0 : 0x18 Main Radio Control State Machine configuration
+type Mcsm0 struct {
+	AutoCal     AutoCal // FS_AUTOCAL
+	POTimeout   byte    // PO_TIMEOUT, 2 bits
+	PinControl  bool    // PIN_CTRL_EN
+	XOSCForceOn bool    // XOSC_FORCE_ON
+}
+
+func (r *Mcsm0) Addr() byte { return MCSM0 }
+func (r *Mcsm0) Encode() byte {
+	return (byte(r.AutoCal)&0x03)<<4 | (r.POTimeout&0x03)<<2 | bit(r.PinControl, 1) | bit(r.XOSCForceOn, 0)
+}
+func (r *Mcsm0) Decode(v byte) error {
+	r.AutoCal = AutoCal((v >> 4) & 0x03)
+	r.POTimeout = (v >> 2) & 0x03
+	r.PinControl = v&0x02 != 0
+	r.XOSCForceOn = v&0x01 != 0
+	return checkReserved(MCSM0, v, 0xC0)
+}
+func (r *Mcsm0) fields() []field {
+	return []field{{"FS_AUTOCAL", int(r.AutoCal)}, {"PO_TIMEOUT", int(r.POTimeout)}, {"PIN_CTRL_EN", boolValue(r.PinControl)}, {"XOSC_FORCE_ON", boolValue(r.XOSCForceOn)}}
+}
+
+// Foccfg : 0x19 Frequency Offset Compensation configuration
+type Foccfg struct {
+	BSCSGate bool // FOC_BS_CS_GATE
+	PreK     byte // FOC_PRE_K, 2 bits
+	PostK    bool // FOC_POST_K
+	Limit    byte // FOC_LIMIT, 2 bits
+}
+
+func (r *Foccfg) Addr() byte { return FOCCFG }
+func (r *Foccfg) Encode() byte {
+	return bit(r.BSCSGate, 5) | (r.PreK&0x03)<<3 | bit(r.PostK, 2) | r.Limit&0x03
+}
+func (r *Foccfg) Decode(v byte) error {
+	r.BSCSGate = v&0x20 != 0
+	r.PreK = (v >> 3) & 0x03
+	r.PostK = v&0x04 != 0
+	r.Limit = v & 0x03
+	return checkReserved(FOCCFG, v, 0xC0)
+}
+func (r *Foccfg) fields() []field {
+	return []field{{"FOC_BS_CS_GATE", boolValue(r.BSCSGate)}, {"FOC_PRE_K", int(r.PreK)}, {"FOC_POST_K", boolValue(r.PostK)}, {"FOC_LIMIT", int(r.Limit)}}
+}
+
+// Bscfg : 0x1A Bit Synchronization configuration
+type Bscfg struct {
+	PreKI  byte // BS_PRE_KI, 2 bits
+	PreKP  byte // BS_PRE_KP, 2 bits
+	PostKI bool // BS_POST_KI
+	PostKP bool // BS_POST_KP
+	Limit  byte // BS_LIMIT, 2 bits
+}
+
+func (r *Bscfg) Addr() byte { return BSCFG }
+func (r *Bscfg) Encode() byte {
+	return (r.PreKI&0x03)<<6 | (r.PreKP&0x03)<<4 | bit(r.PostKI, 3) | bit(r.PostKP, 2) | r.Limit&0x03
+}
+func (r *Bscfg) Decode(v byte) error {
+	r.PreKI = v >> 6
+	r.PreKP = (v >> 4) & 0x03
+	r.PostKI = v&0x08 != 0
+	r.PostKP = v&0x04 != 0
+	r.Limit = v & 0x03
+	return nil
+}
+func (r *Bscfg) fields() []field {
+	return []field{{"BS_PRE_KI", int(r.PreKI)}, {"BS_PRE_KP", int(r.PreKP)}, {"BS_POST_KI", boolValue(r.PostKI)}, {"BS_POST_KP", boolValue(r.PostKP)}, {"BS_LIMIT", int(r.Limit)}}
+}
+
+// Agcctrl2 : 0x1B AGC control
+type Agcctrl2 struct {
+	MaxDVGAGain byte // MAX_DVGA_GAIN, 2 bits
+	MaxLNAGain  byte // MAX_LNA_GAIN, 3 bits
+	MagnTarget  byte // MAGN_TARGET, 3 bits
+}
+
+func (r *Agcctrl2) Addr() byte { return AGCCTRL2 }
+func (r *Agcctrl2) Encode() byte {
+	return (r.MaxDVGAGain&0x03)<<6 | (r.MaxLNAGain&0x07)<<3 | r.MagnTarget&0x07
+}
+func (r *Agcctrl2) Decode(v byte) error {
+	r.MaxDVGAGain = v >> 6
+	r.MaxLNAGain = (v >> 3) & 0x07
+	r.MagnTarget = v & 0x07
+	return nil
+}
+func (r *Agcctrl2) fields() []field {
+	return []field{{"MAX_DVGA_GAIN", int(r.MaxDVGAGain)}, {"MAX_LNA_GAIN", int(r.MaxLNAGain)}, {"MAGN_TARGET", int(r.MagnTarget)}}
+}
+
+// Agcctrl1 : 0x1C AGC control
+type Agcctrl1 struct {
+	LNAPriority        bool // AGC_LNA_PRIORITY
+	CarrierSenseRelThr byte // CARRIER_SENSE_REL_THR, 2 bits
+	CarrierSenseAbsThr int8 // CARRIER_SENSE_ABS_THR, -8 (disabled) to 7 dB
+}
+
+func (r *Agcctrl1) Addr() byte { return AGCCTRL1 }
+func (r *Agcctrl1) Encode() byte {
+	return bit(r.LNAPriority, 6) | (r.CarrierSenseRelThr&0x03)<<4 | byte(r.CarrierSenseAbsThr)&0x0F
+}
+func (r *Agcctrl1) Decode(v byte) error {
+	r.LNAPriority = v&0x40 != 0
+	r.CarrierSenseRelThr = (v >> 4) & 0x03
+	r.CarrierSenseAbsThr = int8(v<<4) >> 4
+	return checkReserved(AGCCTRL1, v, 0x80)
+}
+func (r *Agcctrl1) fields() []field {
+	return []field{{"AGC_LNA_PRIORITY", boolValue(r.LNAPriority)}, {"CARRIER_SENSE_REL_THR", int(r.CarrierSenseRelThr)}, {"CARRIER_SENSE_ABS_THR", int(r.CarrierSenseAbsThr)}}
+}
+
+// Agcctrl0 : 0x1D AGC control
+type Agcctrl0 struct {
+	HystLevel    byte // HYST_LEVEL, 2 bits
+	WaitTime     byte // WAIT_TIME, 2 bits
+	Freeze       byte // AGC_FREEZE, 2 bits
+	FilterLength byte // FILTER_LENGTH, 2 bits
+}
+
+func (r *Agcctrl0) Addr() byte { return AGCCTRL0 }
+func (r *Agcctrl0) Encode() byte {
+	return (r.HystLevel&0x03)<<6 | (r.WaitTime&0x03)<<4 | (r.Freeze&0x03)<<2 | r.FilterLength&0x03
+}
+func (r *Agcctrl0) Decode(v byte) error {
+	r.HystLevel = v >> 6
+	r.WaitTime = (v >> 4) & 0x03
+	r.Freeze = (v >> 2) & 0x03
+	r.FilterLength = v & 0x03
+	return nil
+}
+func (r *Agcctrl0) fields() []field {
+	return []field{{"HYST_LEVEL", int(r.HystLevel)}, {"WAIT_TIME", int(r.WaitTime)}, {"AGC_FREEZE", int(r.Freeze)}, {"FILTER_LENGTH", int(r.FilterLength)}}
+}
+
+// Worctrl : 0x20 Wake On Radio control
+type Worctrl struct {
+	RCPowerDown bool // RC_PD
+	Event1      byte // EVENT1, 3 bits
+	RCCal       bool // RC_CAL
+	WORRes      byte // WOR_RES, 2 bits
+}
+
+func (r *Worctrl) Addr() byte { return WORCTRL }
+func (r *Worctrl) Encode() byte {
+	return bit(r.RCPowerDown, 7) | (r.Event1&0x07)<<4 | bit(r.RCCal, 3) | r.WORRes&0x03
+}
+func (r *Worctrl) Decode(v byte) error {
+	r.RCPowerDown = v&0x80 != 0
+	r.Event1 = (v >> 4) & 0x07
+	r.RCCal = v&0x08 != 0
+	r.WORRes = v & 0x03
+	return checkReserved(WORCTRL, v, 0x04)
+}
+func (r *Worctrl) fields() []field {
+	return []field{{"RC_PD", boolValue(r.RCPowerDown)}, {"EVENT1", int(r.Event1)}, {"RC_CAL", boolValue(r.RCCal)}, {"WOR_RES", int(r.WORRes)}}
+}
+
+// Frend1 : 0x21 Front end RX configuration
+type Frend1 struct {
+	LNACurrent        byte // LNA_CURRENT, 2 bits
+	LNA2MixCurrent    byte // LNA2MIX_CURRENT, 2 bits
+	LODivBufCurrentRX byte // LODIV_BUF_CURRENT_RX, 2 bits
+	MixCurrent        byte // MIX_CURRENT, 2 bits
+}
+
+func (r *Frend1) Addr() byte { return FREND1 }
+func (r *Frend1) Encode() byte {
+	return (r.LNACurrent&0x03)<<6 | (r.LNA2MixCurrent&0x03)<<4 | (r.LODivBufCurrentRX&0x03)<<2 | r.MixCurrent&0x03
+}
+func (r *Frend1) Decode(v byte) error {
+	r.LNACurrent = v >> 6
+	r.LNA2MixCurrent = (v >> 4) & 0x03
+	r.LODivBufCurrentRX = (v >> 2) & 0x03
+	r.MixCurrent = v & 0x03
+	return nil
+}
+func (r *Frend1) fields() []field {
+	return []field{{"LNA_CURRENT", int(r.LNACurrent)}, {"LNA2MIX_CURRENT", int(r.LNA2MixCurrent)}, {"LODIV_BUF_CURRENT_RX", int(r.LODivBufCurrentRX)}, {"MIX_CURRENT", int(r.MixCurrent)}}
+}
+
+// Frend0 : 0x22 Front end TX configuration
+type Frend0 struct {
+	LODivBufCurrentTX byte // LODIV_BUF_CURRENT_TX, 2 bits
+	PAPower           byte // PA_POWER, PATABLE index 0-7
+}
+
+func (r *Frend0) Addr() byte { return FREND0 }
+func (r *Frend0) Encode() byte {
+	return (r.LODivBufCurrentTX&0x03)<<4 | r.PAPower&0x07
+}
+func (r *Frend0) Decode(v byte) error {
+	r.LODivBufCurrentTX = (v >> 4) & 0x03
+	r.PAPower = v & 0x07
+	return checkReserved(FREND0, v, 0xC8)
+}
+func (r *Frend0) fields() []field {
+	return []field{{"LODIV_BUF_CURRENT_TX", int(r.LODivBufCurrentTX)}, {"PA_POWER", int(r.PAPower)}}
+}
+
+// Fscal3 : 0x23 Frequency synthesizer calibration
+type Fscal3 struct {
+	Config          byte // FSCAL3[7:6]
+	ChargePumpCalEn byte // CHP_CURR_CAL_EN, 2 bits
+	Result          byte // FSCAL3[3:0], calibration result
+}
+
+func (r *Fscal3) Addr() byte { return FSCAL3 }
+func (r *Fscal3) Encode() byte {
+	return (r.Config&0x03)<<6 | (r.ChargePumpCalEn&0x03)<<4 | r.Result&0x0F
+}
+func (r *Fscal3) Decode(v byte) error {
+	r.Config = v >> 6
+	r.ChargePumpCalEn = (v >> 4) & 0x03
+	r.Result = v & 0x0F
+	return nil
+}
+func (r *Fscal3) fields() []field {
+	return []field{{"FSCAL3[7:6]", int(r.Config)}, {"CHP_CURR_CAL_EN", int(r.ChargePumpCalEn)}, {"FSCAL3[3:0]", int(r.Result)}}
+}
+
+// Fscal2 : 0x24 Frequency synthesizer calibration
+type Fscal2 struct {
+	VCOCoreHigh bool // VCO_CORE_H_EN
+	Result      byte // FSCAL2, 5 bits
+}
+
+func (r *Fscal2) Addr() byte { return FSCAL2 }
+func (r *Fscal2) Encode() byte {
+	return bit(r.VCOCoreHigh, 5) | r.Result&0x1F
+}
+func (r *Fscal2) Decode(v byte) error {
+	r.VCOCoreHigh = v&0x20 != 0
+	r.Result = v & 0x1F
+	return checkReserved(FSCAL2, v, 0xC0)
+}
+func (r *Fscal2) fields() []field {
+	return []field{{"VCO_CORE_H_EN", boolValue(r.VCOCoreHigh)}, {"FSCAL2", int(r.Result)}}
+}
+
+// Fscal1 : 0x25 Frequency synthesizer calibration
+type Fscal1 struct {
+	Result byte // FSCAL1, 6 bits
+}
+
+func (r *Fscal1) Addr() byte   { return FSCAL1 }
+func (r *Fscal1) Encode() byte { return r.Result & 0x3F }
+func (r *Fscal1) Decode(v byte) error {
+	r.Result = v & 0x3F
+	return checkReserved(FSCAL1, v, 0xC0)
+}
+func (r *Fscal1) fields() []field {
+	return []field{{"FSCAL1", int(r.Result)}}
+}
+
+// Fscal0 : 0x26 Frequency synthesizer calibration
+type Fscal0 struct {
+	Value byte // FSCAL0, 7 bits
+}
+
+func (r *Fscal0) Addr() byte   { return FSCAL0 }
+func (r *Fscal0) Encode() byte { return r.Value & 0x7F }
+func (r *Fscal0) Decode(v byte) error {
+	r.Value = v & 0x7F
+	return checkReserved(FSCAL0, v, 0x80)
+}
+func (r *Fscal0) fields() []field {
+	return []field{{"FSCAL0", int(r.Value)}}
+}
+
+// Rcctrl1 : 0x27 RC oscillator configuration
+type Rcctrl1 struct {
+	Value byte // RCCTRL1, 7 bits
+}
+
+func (r *Rcctrl1) Addr() byte   { return RCCTRL1 }
+func (r *Rcctrl1) Encode() byte { return r.Value & 0x7F }
+func (r *Rcctrl1) Decode(v byte) error {
+	r.Value = v & 0x7F
+	return checkReserved(RCCTRL1, v, 0x80)
+}
+func (r *Rcctrl1) fields() []field {
+	return []field{{"RCCTRL1", int(r.Value)}}
+}
+
+// Rcctrl0 : 0x28 RC oscillator configuration
+type Rcctrl0 struct {
+	Value byte // RCCTRL0, 7 bits
+}
+
+func (r *Rcctrl0) Addr() byte   { return RCCTRL0 }
+func (r *Rcctrl0) Encode() byte { return r.Value & 0x7F }
+func (r *Rcctrl0) Decode(v byte) error {
+	r.Value = v & 0x7F
+	return checkReserved(RCCTRL0, v, 0x80)
+}
+func (r *Rcctrl0) fields() []field {
+	return []field{{"RCCTRL0", int(r.Value)}}
+}
+
+// Test0 : 0x2E Various test settings
+type Test0 struct {
+	High      byte // TEST0[7:2], 6 bits
+	VCOSelCal bool // VCO_SEL_CAL_EN
+	Low       byte // TEST0[0]
+}
+
+func (r *Test0) Addr() byte { return TEST0 }
+func (r *Test0) Encode() byte {
+	return (r.High&0x3F)<<2 | bit(r.VCOSelCal, 1) | r.Low&0x01
+}
+func (r *Test0) Decode(v byte) error {
+	r.High = v >> 2
+	r.VCOSelCal = v&0x02 != 0
+	r.Low = v & 0x01
+	return nil
+}
+func (r *Test0) fields() []field {
+	return []field{{"TEST0[7:2]", int(r.High)}, {"VCO_SEL_CAL_EN", boolValue(r.VCOSelCal)}, {"TEST0[0]", int(r.Low)}}
+}
+
+// newRegister returns the typed model of the register at addr, or nil for
+// the registers holding a single 8-bit value: SYNC1/0, PKTLEN, ADDR,
+// CHANNR, FSCTRL0, FREQ1/0, WOREVT1/0 and FSTEST-TEST1.
+func newRegister(addr byte) Register {
+	switch addr {
+	case IOCFG2:
+		return new(Iocfg2)
+	case IOCFG1:
+		return new(Iocfg1)
+	case IOCFG0:
+		return new(Iocfg0)
+	case FIFOTHR:
+		return new(Fifothr)
+	case PKTCTRL1:
+		return new(Pktctrl1)
+	case PKTCTRL0:
+		return new(Pktctrl0)
+	case FSCTRL1:
+		return new(Fsctrl1)
+	case FREQ2:
+		return new(Freq2)
+	case MDMCFG4:
+		return new(Mdmcfg4)
+	case MDMCFG3:
+		return new(Mdmcfg3)
+	case MDMCFG2:
+		return new(Mdmcfg2)
+	case MDMCFG1:
+		return new(Mdmcfg1)
+	case MDMCFG0:
+		return new(Mdmcfg0)
+	case DEVIATN:
+		return new(Deviatn)
+	case MCSM2:
+		return new(Mcsm2)
+	case MCSM1:
+		return new(Mcsm1)
+	case MCSM0:
+		return new(Mcsm0)
+	case FOCCFG:
+		return new(Foccfg)
+	case BSCFG:
+		return new(Bscfg)
+	case AGCCTRL2:
+		return new(Agcctrl2)
+	case AGCCTRL1:
+		return new(Agcctrl1)
+	case AGCCTRL0:
+		return new(Agcctrl0)
+	case WORCTRL:
+		return new(Worctrl)
+	case FREND1:
+		return new(Frend1)
+	case FREND0:
+		return new(Frend0)
+	case FSCAL3:
+		return new(Fscal3)
+	case FSCAL2:
+		return new(Fscal2)
+	case FSCAL1:
+		return new(Fscal1)
+	case FSCAL0:
+		return new(Fscal0)
+	case RCCTRL1:
+		return new(Rcctrl1)
+	case RCCTRL0:
+		return new(Rcctrl0)
+	case TEST0:
+		return new(Test0)
+	}
+	return nil
+}
+
+// ReadRegister reads the chip register behind r and decodes it into r.
+func (d *Device) ReadRegister(r Register) error {
+	value, err := d.ReadSingleRegister(r.Addr())
+	if err != nil {
+		return err
+	}
+	return r.Decode(value)
+}
+
+// WriteRegister encodes r and writes it to the chip.
+func (d *Device) WriteRegister(r Register) error {
+	return d.WriteSingleRegister(r.Addr(), r.Encode())
+}
+
+// modify decodes the last value written to r, lets fn change its fields and
+// writes the result back, so the other fields of the register are preserved.
+func (d *Device) modify(r Register, fn func()) error {
+	d.cfgMu.Lock()
+	defer d.cfgMu.Unlock()
+	return d.modifyLocked(r, fn)
+}
+
+// modifyLocked is modify for callers already holding cfgMu.
+func (d *Device) modifyLocked(r Register, fn func()) error {
+	if err := r.Decode(d.shadow(r.Addr())); err != nil {
+		return err
+	}
+	fn()
+	return d.WriteRegister(r)
+}
+
+func (s SyncMode) String() string {
+	names := [...]string{"no preamble/sync", "15/16 sync bits", "16/16 sync bits", "30/32 sync bits"}
+	name := names[s&0x03]
+	if s&syncModeCSFlag != 0 {
+		name += " + carrier-sense"
+	}
+	return name
+}
+
+func (l LengthConfig) String() string {
+	switch l {
+	case LengthFixed:
+		return "fixed"
+	case LengthVariable:
+		return "variable"
+	case LengthInfinite:
+		return "infinite"
+	}
+	return "reserved"
+}
+
+func (f PacketFormat) String() string {
+	return [...]string{"normal (FIFO)", "synchronous serial", "random TX", "asynchronous serial"}[f&0x03]
+}
+
+func (a AddressCheck) String() string {
+	return [...]string{"none", "exact", "exact + 0x00 broadcast", "exact + 0x00/0xFF broadcast"}[a&0x03]
+}
+
+func (o OffMode) String() string {
+	return [...]string{"IDLE", "FSTXON", "TX", "RX"}[o&0x03]
+}
+
+func (c CCAMode) String() string {
+	return [...]string{"always", "RSSI below threshold", "unless receiving a packet", "RSSI below threshold unless receiving a packet"}[c&0x03]
+}
+
+func (a AutoCal) String() string {
+	return [...]string{"never", "IDLE to RX/TX", "RX/TX to IDLE", "every 4th RX/TX to IDLE"}[a&0x03]
+}
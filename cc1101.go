@@ -1,8 +1,6 @@
 package cc1101
 
-import (
-		"machine"
-)
+import "sync"
 
 const (
 	CC1101_READSINGLE = 0x80
@@ -11,21 +9,10 @@ const (
 )
 
 var (
-	StateCCMode bool
-	m4RxBw      byte
-
-	// Config for 0x12: MDMCFG2
-	// | DCOFF   | MODFM    | MANCH   | SYNCM    |
-	// | 7th bit | 6-4 bits | 3rd bit | 2-0 bits |
-	// Read page 77 https://www.ti.com/lit/ds/symlink/cc1101.pdf
-	//
-	m2DCOFF, m2MANCH, m2MODFM byte
-	frend0                    byte
-	m2SYNCM                   byte = 0x02
+	frend0 byte
 	// Set Frequency vars
 	freq0, freq1, freq2 byte
 	mhz                 float32
-	marcstate           byte
 )
 
 type SPI interface {
@@ -34,23 +21,65 @@ type SPI interface {
 
 type PinOutput func(state bool)
 
+// PinInput reads the level of a pin, e.g. machine.Pin.Get for MISO.
+type PinInput func() bool
+
 type Device struct {
 	bus  SPI
 	cs   PinOutput
-	miso machine.Pin
+	miso PinInput
+
+	// mu is held for each SPI transaction and guards the fields below.
+	mu sync.Mutex
+
+	// Optional lock shared with the other drivers on the SPI bus, held
+	// while CS is asserted.
+	busLock sync.Locker
+
+	// Last value written to each configuration register and to the
+	// PATABLE, starting from the reset defaults.
+	regs    [CFG_REGISTER]byte
+	patable [PATABLE_SIZE]byte
+
+	// shadowKnown is set once the shadow holds the whole chip
+	// configuration: after Reset, Restore or LoadShadow.
+	shadowKnown bool
+
+	// Status byte returned by the last SPI transaction.
+	status StatusByte
+
+	// Buffers reused by every SPI transaction, so that register I/O does
+	// not allocate.
+	txBuf, rxBuf [spiBufferSize]byte
+
+	fifoStats  FIFOStats
+	scrubStats ScrubStats
+
+	// Optional transaction tracer, and the record passed to it.
+	tracer Tracer
+	trace  Transaction
+
+	// cfgMu makes read-modify-write register updates atomic.
+	cfgMu sync.Mutex
+
+	// txMu and rxMu serialise packet transmission and reception, and
+	// guard the FIFO buffers used by each.
+	txMu, rxMu     sync.Mutex
+	txFIFO, rxFIFO [FIFOBUFFER]byte
 }
-func New(bus SPI, cs PinOutput, miso machine.Pin) *Device {
-	device := Device{bus: bus, cs: cs, miso: miso}
+func New(bus SPI, cs PinOutput, miso PinInput) *Device {
+	device := Device{bus: bus, cs: cs, miso: miso, regs: resetDefaults, patable: resetPATable}
 	return &device
 }
 
+// IsConnected reports whether a known chip answers, reading PARTNUM and
+// VERSION only: unlike Identify it never writes a register.
+//
+// Deprecated: use Identify, which also reports the chip model and why the
+// check failed.
 func (d *Device) IsConnected() bool {
-	d.EnableCS()
-	state, _ := d.ReadSingleRegister(0x31)
-	if state > 0 {
-		return true
-	}
-	return false
+	_, err := d.identify()
+	return err == nil
 }
 
 
@@ -0,0 +1,54 @@
+// Command cc1101dump prints an annotated report of a CC1101 register dump.
+//
+// The dump is the 47 configuration registers 0x00-0x2E as hex bytes,
+// optionally followed by the 8 PATABLE bytes, read from the file given as
+// argument or from standard input:
+//
+//	cc1101dump dump.txt
+//	echo "29 2E 06 47 D3 91 ..." | cc1101dump
+package main
+
+import (
+	"cc1101"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "cc1101dump:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	in := stdin
+	switch len(args) {
+	case 0:
+	case 1:
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	default:
+		return fmt.Errorf("usage: cc1101dump [file]")
+	}
+
+	text, err := io.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	dump, err := cc1101.ParseHexDump(string(text))
+	if err != nil {
+		return err
+	}
+	report, err := cc1101.DecodeDump(dump)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(stdout, report.String())
+	return err
+}
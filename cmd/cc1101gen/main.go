@@ -0,0 +1,147 @@
+// Command cc1101gen turns a radio configuration into Go source that firmware
+// can embed: a register table and an apply function, with nothing to compute
+// or parse at run time.
+//
+// The input is a radioconf JSON or YAML file (.json, .yaml, .yml) or a
+// SmartRF Studio export (any other extension). The configuration is checked
+// before any code is written: radioconf files must pass RadioConfig.Build,
+// and SmartRF exports must decode without reserved bit or field errors.
+//
+//	cc1101gen -name Telemetry -pkg main -o telemetry_radio.go telemetry.yaml
+//
+// generates
+//
+//	var telemetrySnapshot = cc1101.Snapshot{...}
+//	func ApplyTelemetry(d *cc1101.Device) error
+//
+// It is meant to be used from a go:generate directive.
+package main
+
+import (
+	"bytes"
+	"cc1101"
+	"cc1101/radioconf"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "cc1101gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("cc1101gen", flag.ContinueOnError)
+	name := fs.String("name", "Radio", "configuration name, generates Apply<name> and <name>Snapshot")
+	pkg := fs.String("pkg", "main", "package of the generated file")
+	out := fs.String("o", "", "output file (default standard output)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: cc1101gen [-name Name] [-pkg pkg] [-o file.go] config")
+	}
+	if !token.IsIdentifier(*name) || !token.IsIdentifier(*pkg) {
+		return fmt.Errorf("-name and -pkg must be Go identifiers")
+	}
+	input := fs.Arg(0)
+
+	data, err := os.ReadFile(input)
+	if err != nil {
+		return err
+	}
+	snap, err := load(input, data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", input, err)
+	}
+	src, err := generate(snap, *name, *pkg, filepath.Base(input))
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*out, src, 0o644)
+}
+
+// load returns the checked register values described by a configuration file.
+func load(path string, data []byte) (*cc1101.Snapshot, error) {
+	if format, err := radioconf.FormatFromPath(path); err == nil {
+		c, err := radioconf.Unmarshal(data, format)
+		if err != nil {
+			return nil, err
+		}
+		return c.Build()
+	}
+
+	settings, err := cc1101.ParseSmartRF(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	snap := settings.Snapshot()
+	if problems := cc1101.DecodeSnapshot(snap).Problems; len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "; "))
+	}
+	return snap, nil
+}
+
+func generate(snap *cc1101.Snapshot, name, pkg, source string) ([]byte, error) {
+	r, n := utf8.DecodeRuneInString(name)
+	exported := string(unicode.ToUpper(r)) + name[n:]
+	unexported := string(unicode.ToLower(r)) + name[n:]
+	table := unexported + "Snapshot"
+
+	var b bytes.Buffer
+	p := func(format string, args ...interface{}) { fmt.Fprintf(&b, format+"\n", args...) }
+
+	p("// Code generated by cc1101gen from %s. DO NOT EDIT.", source)
+	p("")
+	p("package %s", pkg)
+	p("")
+	p("import \"cc1101\"")
+	p("")
+	p("// %s holds the %s configuration:", table, name)
+	p("//")
+	summary, _, _ := strings.Cut(cc1101.DecodeSnapshot(snap).String(), "\n\n")
+	for _, line := range strings.Split(summary, "\n") {
+		p("//\t%s", line)
+	}
+	p("var %s = cc1101.Snapshot{", table)
+	p("Registers: [cc1101.CFG_REGISTER]byte{")
+	for addr, value := range snap.Registers {
+		p("0x%02X, // 0x%02X %s", value, addr, cc1101.RegisterName(byte(addr)))
+	}
+	p("},")
+	p("PATable: [cc1101.PATABLE_SIZE]byte{")
+	for _, value := range snap.PATable {
+		fmt.Fprintf(&b, "0x%02X, ", value)
+	}
+	p("\n},")
+	p("}")
+	p("")
+	p("// Apply%s resets the chip and loads the %s configuration.", exported, name)
+	p("func Apply%s(d *cc1101.Device) error {", exported)
+	p("if err := d.Reset(); err != nil {")
+	p("return err")
+	p("}")
+	p("return d.Restore(&%s)", table)
+	p("}")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid Go source: %w", err)
+	}
+	return src, nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestGenerateGolden(t *testing.T) {
+	input := filepath.Join("testdata", "telemetry.yaml")
+	data, err := os.ReadFile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snap, err := load(input, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generate(snap, "Telemetry", "main", "telemetry.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "telemetry.go.golden")
+	if *update {
+		if err := os.WriteFile(golden, src, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, want) {
+		t.Errorf("generated source differs from %s:\n%s", golden, src)
+	}
+
+	// The output must compile against the driver.
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "telemetry_radio.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("main", fset, []*ast.File{f}, nil)
+	if err != nil {
+		t.Fatalf("generated source does not type-check: %v", err)
+	}
+	if pkg.Scope().Lookup("ApplyTelemetry") == nil {
+		t.Error("generated source has no ApplyTelemetry")
+	}
+}
+
+func TestLoadRejectsInvalidConfig(t *testing.T) {
+	if _, err := load("bad.yaml", []byte("version: 1\nfrequency_mhz: 500\n")); err == nil {
+		t.Error("load accepted a frequency outside the bands")
+	}
+	if _, err := load("bad.txt", []byte("MDMCFG2 0x22\n")); err == nil {
+		t.Error("load accepted a SmartRF export with an undefined MOD_FORMAT")
+	}
+}
@@ -1,79 +1,23 @@
 package cc1101
 import (
-	"errors"
 	"fmt"
 )
 
 
-func (d *Device) setCCMode(state bool) {
-	StateCCMode = state
-	if StateCCMode == true {
-		d.WriteSingleRegister(IOCFG2, 0x0b)
-		d.WriteSingleRegister(IOCFG0, 0x06)
-		d.WriteSingleRegister(PKTCTRL0, 0x05)
-		d.WriteSingleRegister(MDMCFG3, 0xF8)
-		d.WriteSingleRegister(MDMCFG4, 11+m4RxBw)
-	} else {
-		d.WriteSingleRegister(IOCFG2, 0x0D)
-		d.WriteSingleRegister(IOCFG0, 0x0D)
-		d.WriteSingleRegister(PKTCTRL0, 0x32)
-		d.WriteSingleRegister(MDMCFG3, 0x93)
-		d.WriteSingleRegister(MDMCFG4, 7+m4RxBw)
-	}
-}
-
 func (d *Device) SetSYNC_MODE(choice int) error {
-	switch choice {
-	case 0:
-		m2SYNCM = 0x00 // Aucune synchronisation (pas de préambule/sync)
-	case 1:
-		m2SYNCM = 0x01 // 15/16 bits du mot de synchronisation détectés
-	case 2:
-		m2SYNCM = 0x02 // 16/16 bits du mot de synchronisation détectés
-	case 3:
-		m2SYNCM = 0x03 // 30/32 bits du mot de synchronisation détectés
-	case 4:
-		m2SYNCM = 0x04 // Aucune synchronisation avec détection du seuil de porteuse
-	case 5:
-		m2SYNCM = 0x05 // 15/16 bits du mot de synchronisation + détection du seuil de porteuse
-	case 6:
-		m2SYNCM = 0x06 // 16/16 bits du mot de synchronisation + détection du seuil de porteuse
-	case 7:
-		m2SYNCM = 0x07 // 30/32 bits du mot de synchronisation + détection du seuil de porteuse
-	default:
+	if choice < 0 || choice > int(Sync30of32CS) {
 		return fmt.Errorf("invalid SYNC_MODE choice: %d", choice)
 	}
-	registerValue := (m2DCOFF & 0x80) | (m2MODFM & 0x70) | (m2MANCH & 0x08) | (m2SYNCM & 0x07)
-	err := d.WriteSingleRegister(MDMCFG2, registerValue)
-	if err != nil {
-		return fmt.Errorf("Error writing in the register : %v", err)
-	}
-	return nil
+	return d.setMdmcfg2(func(m *Mdmcfg2) { m.SyncMode = SyncMode(choice) })
 }
 
 
 func (d *Device) SetModulation(modulation string) error {
-    switch modulation {
-    case "2FSK":
-        m2MODFM = 0x00
-    case "GFSK":
-        m2MODFM = 0x10
-    case "OOK":
-        m2MODFM = 0x30
-    case "4FSK":
-        m2MODFM = 0x40
-    case "MSK":
-        m2MODFM = 0x70
-    default:
-        return errors.New("Unsupported modulation type")
-    }
-    
-    registerValue := (m2DCOFF & 0x80) | (m2MODFM & 0x70) | (m2MANCH & 0x08) | (m2SYNCM & 0x07)
-    err := d.WriteSingleRegister(MDMCFG2, registerValue)
+    mod, err := ParseModulation(modulation)
     if err != nil {
-        return fmt.Errorf("Error writing in the register : %v", err)
+        return err
     }
-    return nil
+    return d.setMdmcfg2(func(m *Mdmcfg2) { m.Modulation = mod })
 }
 
 
@@ -107,89 +51,57 @@ func (d *Device) SetFrequency(frequency float32) error {
 }
 
 
-func (d *Device) SetRx() {
-	d.SpiStrobe(SRX)
-	for marcstate != MARCSTATE_RX {
-		marcstate, _ = d.ReadSingleRegister(MARCSTATE)
+// SetTxPower sets the PA power. With OOK modulation PATABLE[0] stays 0x00
+// for a '0' symbol and powerSetting goes to PATABLE[1], used for a '1';
+// otherwise it goes to PATABLE[0]. FREND0 PA_POWER selects the entry.
+func (d *Device) SetTxPower(powerSetting byte) error {
+	// Hold cfgMu so that SetModulation cannot change MDMCFG2 between
+	// choosing the PATABLE index and setting FREND0.
+	d.cfgMu.Lock()
+	defer d.cfgMu.Unlock()
+	var m Mdmcfg2
+	if err := m.Decode(d.shadow(MDMCFG2)); err != nil {
+		return err
 	}
-}
-
-func (d *Device) SetTx() {
-	d.SpiStrobe(STX)
-	for marcstate != MARCSTATE_TX {
-		marcstate, _ = d.ReadSingleRegister(MARCSTATE)
+	index := byte(0)
+	if m.Modulation == ModulationOOK {
+		index = 1
 	}
-}
 
-func (d *Device) SetTxPower(powerSetting byte) error {
 	// CRITIQUE: initialiser TOUS les 8 bytes de la PATABLE
 	// Sinon le CC1101 peut utiliser des valeurs indéfinies
-	paTable := []byte{
-		powerSetting, // Index 0 (utilisé si FREND0 = 0x10)
-		0x00,         // Index 1
-		0x00,         // Index 2
-		0x00,         // Index 3
-		0x00,         // Index 4
-		0x00,         // Index 5
-		0x00,         // Index 6
-		0x00,         // Index 7
-	}
-
-	err := d.WriteBurstRegister(PATABLE, paTable)
-	if err != nil {
+	var paTable [PATABLE_SIZE]byte
+	paTable[index] = powerSetting
+	if err := d.WriteBurstRegister(PATABLE, paTable[:]); err != nil {
 		return err
 	}
-	
-	// FREND0 = 0x10 signifie utiliser PATABLE[0]
-	return d.WriteSingleRegister(FREND0, 0x10)
-}
-
 
-// The mask ensures that only the relevant bits are modified in the register
-// while leaving the others unchanged.
-// The following mask operations isolate specific bits for each field:
-// - (m2DCOFF & 0x80) keeps only bit 7 for DCOFF.
-// - (m2MODFM & 0x70) keeps bits 6-4 for MODFM (modulation type).
-// - (m2MANCH & 0x08) keeps bit 3 for Manchester encoding.
-// - (m2SYNCM & 0x07) keeps bits 2-0 for SYNC mode.
-//
-// These are combined using the OR operator (|) to form the final register value.
+	var f Frend0
+	return d.modifyLocked(&f, func() { f.PAPower = index })
+}
 
 
 func (d *Device) EnableManchester() error {
-	m2MANCH = 0x08
-	registerValue := (m2DCOFF & 0x80) | (m2MODFM & 0x70) | (m2MANCH & 0x08) | (m2SYNCM & 0x07)
-	err := d.WriteSingleRegister(MDMCFG2, registerValue)
-	if err != nil {
-		return fmt.Errorf("Error writing in the register : %v", err)
-	}
-	return nil
+	return d.setMdmcfg2(func(m *Mdmcfg2) { m.Manchester = true })
 }
 
 func (d *Device) DisableManchester() error {
-	m2MANCH = 0x00
-	registerValue := (m2DCOFF & 0x80) | (m2MODFM & 0x70) | (m2MANCH & 0x08) | (m2SYNCM & 0x07)
-	err := d.WriteSingleRegister(MDMCFG2, registerValue)
-	if err != nil {
-		return fmt.Errorf("Error writing in the register : %v", err)
-	}
-	return nil
+	return d.setMdmcfg2(func(m *Mdmcfg2) { m.Manchester = false })
 }
 
 func (d *Device) EnableDCFilter() error {
-	m2DCOFF = 0x80
-	registerValue := (m2DCOFF & 0x80) | (m2MODFM & 0x70) | (m2MANCH & 0x08) | (m2SYNCM & 0x07)
-	err := d.WriteSingleRegister(MDMCFG2, registerValue)
-	if err != nil {
-		return fmt.Errorf("Error writing in the register : %v", err)
-	}
-	return nil
+	return d.setMdmcfg2(func(m *Mdmcfg2) { m.DCFilterOff = false })
 }
 
 func (d *Device) DisableDCFilter() error {
-	m2DCOFF = 0x00
-	registerValue := (m2DCOFF & 0x80) | (m2MODFM & 0x70) | (m2MANCH & 0x08) | (m2SYNCM & 0x07)
-	err := d.WriteSingleRegister(MDMCFG2, registerValue)
+	return d.setMdmcfg2(func(m *Mdmcfg2) { m.DCFilterOff = true })
+}
+
+// setMdmcfg2 changes MDMCFG2 through the register model, leaving the fields
+// fn does not touch unchanged.
+func (d *Device) setMdmcfg2(fn func(m *Mdmcfg2)) error {
+	var m Mdmcfg2
+	err := d.modify(&m, func() { fn(&m) })
 	if err != nil {
 		return fmt.Errorf("Error writing in the register : %v", err)
 	}
@@ -0,0 +1,56 @@
+package cc1101
+
+import "testing"
+
+func TestSetTxPower(t *testing.T) {
+	tests := []struct {
+		name    string
+		preset  *Preset
+		paPower byte
+		patable [PATABLE_SIZE]byte
+	}{
+		{"GFSK", &PresetGFSK868, 0, [PATABLE_SIZE]byte{Power_0dBm}},
+		{"OOK", &PresetOOK433, 1, [PATABLE_SIZE]byte{0x00, Power_0dBm}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, chip := newFakeDevice()
+			if err := d.ApplyPreset(tt.preset); err != nil {
+				t.Fatal(err)
+			}
+			frend0 := chip.regs[FREND0]
+			if err := d.SetTxPower(Power_0dBm); err != nil {
+				t.Fatal(err)
+			}
+			if chip.patable != tt.patable {
+				t.Errorf("PATABLE = % X, want % X", chip.patable, tt.patable)
+			}
+			if got := chip.regs[FREND0] & 0x07; got != tt.paPower {
+				t.Errorf("FREND0 PA_POWER = %d, want %d", got, tt.paPower)
+			}
+			if got, want := chip.regs[FREND0]&^0x07, frend0&^0x07; got != want {
+				t.Errorf("FREND0 other bits = 0x%02X, want 0x%02X", got, want)
+			}
+		})
+	}
+}
+
+func TestModifyShadowDecodeError(t *testing.T) {
+	d, chip := newFakeDevice()
+	// MOD_FORMAT 2 is reserved, so the MDMCFG2 shadow does not decode.
+	if err := d.WriteSingleRegister(MDMCFG2, 0x22); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.EnableManchester(); err == nil {
+		t.Error("EnableManchester succeeded with an invalid MDMCFG2")
+	}
+	if got := chip.regs[MDMCFG2]; got != 0x22 {
+		t.Errorf("MDMCFG2 = 0x%02X after a failed update, want 0x22", got)
+	}
+	if err := d.SetTxPower(Power_0dBm); err == nil {
+		t.Error("SetTxPower succeeded with an invalid MDMCFG2")
+	}
+	if chip.patable != resetPATable {
+		t.Errorf("PATABLE = % X after a failed SetTxPower, want % X", chip.patable, resetPATable)
+	}
+}
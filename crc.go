@@ -0,0 +1,49 @@
+package cc1101
+
+// Software versions of the packet handler's CRC and data whitening, for
+// packets captured off the air, generated for other radios, or sent in
+// infinite length and serial modes where the packet handler is bypassed.
+//
+// When both are enabled the chip computes the CRC over the length byte,
+// address and payload, appends it MSB first, then whitens everything sent
+// after the sync word, CRC included.
+
+// CRC16 is the packet CRC: polynomial 0x8005, initial value 0xFFFF, bits
+// processed MSB first, no final XOR.
+func CRC16(data []byte) uint16 {
+	return CRC16Update(0xFFFF, data)
+}
+
+// CRC16Update continues a CRC16 computation over more data.
+func CRC16Update(crc uint16, data []byte) uint16 {
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x8005
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// AppendCRC16 appends the CRC16 of data, MSB first, as the chip sends it.
+func AppendCRC16(data []byte) []byte {
+	crc := CRC16(data)
+	return append(data, byte(crc>>8), byte(crc))
+}
+
+// Whiten XORs data in place with the PN9 sequence (x^9 + x^5 + 1, seeded
+// with all ones) used by WHITE_DATA. Whitening is its own inverse, so the
+// same call removes it from received data.
+func Whiten(data []byte) {
+	key := uint16(0x1FF)
+	for i := range data {
+		data[i] ^= byte(key)
+		for j := 0; j < 8; j++ {
+			key = key>>1 | ((key^key>>5)&1)<<8
+		}
+	}
+}
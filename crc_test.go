@@ -0,0 +1,68 @@
+package cc1101
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCRC16(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want uint16
+	}{
+		{nil, 0xFFFF}, // initial value, nothing processed
+		// Standard check value of this CRC (poly 0x8005, init 0xFFFF, MSB
+		// first, no final XOR), catalogued as CRC-16/CMS.
+		{[]byte("123456789"), 0xAEE7},
+	}
+	for _, tt := range tests {
+		if got := CRC16(tt.data); got != tt.want {
+			t.Errorf("CRC16(%q) = 0x%04X, want 0x%04X", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestCRC16Update(t *testing.T) {
+	data := []byte("123456789")
+	if got := CRC16Update(CRC16(data[:4]), data[4:]); got != CRC16(data) {
+		t.Errorf("CRC16Update over two parts = 0x%04X, want 0x%04X", got, CRC16(data))
+	}
+}
+
+func TestAppendCRC16(t *testing.T) {
+	packet := AppendCRC16([]byte{0x03, 0x01, 0x02, 0x03})
+	if len(packet) != 6 {
+		t.Fatalf("AppendCRC16 returned %d bytes, want 6", len(packet))
+	}
+	// With no final XOR, the CRC over data and its CRC is zero, which is
+	// how the receiver checks it.
+	if got := CRC16(packet); got != 0 {
+		t.Errorf("CRC16 over data and CRC = 0x%04X, want 0", got)
+	}
+}
+
+func TestWhitenPN9(t *testing.T) {
+	// Whitening zeros gives the PN9 sequence itself.
+	want := []byte{
+		0xFF, 0xE1, 0x1D, 0x9A, 0xED, 0x85, 0x33, 0x24,
+		0xEA, 0x7A, 0xD2, 0x39, 0x70, 0x97, 0x57, 0x0A,
+	}
+	got := make([]byte, len(want))
+	Whiten(got)
+	if !bytes.Equal(got, want) {
+		t.Errorf("PN9 = % X\nwant  % X", got, want)
+	}
+}
+
+func TestWhitenRoundTrip(t *testing.T) {
+	data := []byte("whitening is its own inverse, over more than 64 bytes of data....")
+	buf := append([]byte(nil), data...)
+	Whiten(buf)
+	if bytes.Equal(buf, data) {
+		t.Fatal("Whiten did not change the data")
+	}
+	Whiten(buf)
+	if !bytes.Equal(buf, data) {
+		t.Errorf("dewhitened % X, want % X", buf, data)
+	}
+}
@@ -0,0 +1,297 @@
+package cc1101
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Report is the decoded form of a register dump, in physical units where the
+// datasheet defines them.
+type Report struct {
+	Registers [CFG_REGISTER]byte
+	PATable   []byte // nil if the dump did not include the PATABLE
+
+	FrequencyHz      float64 // Base frequency
+	Channel          byte
+	ChannelSpacingHz float64
+	CarrierHz        float64 // Base frequency + channel * spacing
+	IFHz             float64
+	DataRate         float64 // Baud
+	RxBandwidthHz    float64
+	DeviationHz      float64
+	SyncWord         uint16
+	PreambleBytes    int
+	PacketLength     byte
+	Address          byte
+
+	Iocfg2   Iocfg2
+	Iocfg1   Iocfg1
+	Iocfg0   Iocfg0
+	Pktctrl1 Pktctrl1
+	Pktctrl0 Pktctrl0
+	Mdmcfg2  Mdmcfg2
+	Mdmcfg1  Mdmcfg1
+	Mcsm2    Mcsm2
+	Mcsm1    Mcsm1
+	Mcsm0    Mcsm0
+	Frend0   Frend0
+
+	// Reserved bits or undefined values found while decoding.
+	Problems []string
+}
+
+// ParseHexDump extracts the register values from pasted hex: bytes
+// separated by spaces, commas or new lines, with or without a 0x prefix.
+// Tokens ending with ':' are taken as address labels and skipped.
+func ParseHexDump(text string) ([]byte, error) {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return strings.ContainsRune(" \t\r\n,;{}[]", r)
+	})
+	var dump []byte
+	for _, f := range fields {
+		if strings.HasSuffix(f, ":") {
+			continue
+		}
+		f = strings.TrimPrefix(strings.TrimPrefix(f, "0x"), "0X")
+		v, err := strconv.ParseUint(f, 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid byte %q in dump", f)
+		}
+		dump = append(dump, byte(v))
+	}
+	return dump, nil
+}
+
+// DecodeDump decodes the registers 0x00-0x2E, as returned by
+// ReadBurstRegister(IOCFG2, CFG_REGISTER), optionally followed by the 8
+// PATABLE bytes.
+func DecodeDump(dump []byte) (*Report, error) {
+	if len(dump) != CFG_REGISTER && len(dump) != CFG_REGISTER+PATABLE_SIZE {
+		return nil, fmt.Errorf("dump has %d bytes, want %d or %d", len(dump), CFG_REGISTER, CFG_REGISTER+PATABLE_SIZE)
+	}
+	var r Report
+	copy(r.Registers[:], dump)
+	if len(dump) > CFG_REGISTER {
+		r.PATable = append([]byte(nil), dump[CFG_REGISTER:]...)
+	}
+	r.decode()
+	return &r, nil
+}
+
+// DecodeSnapshot decodes the registers and PATABLE held in s.
+func DecodeSnapshot(s *Snapshot) *Report {
+	r := Report{Registers: s.Registers, PATable: append([]byte(nil), s.PATable[:]...)}
+	r.decode()
+	return &r
+}
+
+func (r *Report) decode() {
+	regs := &r.Registers
+	for _, reg := range []Register{&r.Iocfg2, &r.Iocfg1, &r.Iocfg0, &r.Pktctrl1, &r.Pktctrl0,
+		&r.Mdmcfg2, &r.Mdmcfg1, &r.Mcsm2, &r.Mcsm1, &r.Mcsm0, &r.Frend0} {
+		if err := reg.Decode(regs[reg.Addr()]); err != nil {
+			r.Problems = append(r.Problems, err.Error())
+		}
+	}
+	var m4 Mdmcfg4
+	var dev Deviatn
+	var fs Fsctrl1
+	m4.Decode(regs[MDMCFG4])
+	if err := dev.Decode(regs[DEVIATN]); err != nil {
+		r.Problems = append(r.Problems, err.Error())
+	}
+	if err := fs.Decode(regs[FSCTRL1]); err != nil {
+		r.Problems = append(r.Problems, err.Error())
+	}
+
+	r.FrequencyHz = FrequencyHz(regs[FREQ2], regs[FREQ1], regs[FREQ0])
+	r.Channel = regs[CHANNR]
+	r.ChannelSpacingHz = ChannelSpacing(r.Mdmcfg1.ChanSpcE, regs[MDMCFG0])
+	r.CarrierHz = r.FrequencyHz + float64(r.Channel)*r.ChannelSpacingHz
+	r.IFHz = IntermediateFrequency(fs.FreqIF)
+	r.DataRate = DataRate(m4.DrateE, regs[MDMCFG3])
+	r.RxBandwidthHz = RxBandwidth(m4.ChanBwE, m4.ChanBwM)
+	r.DeviationHz = Deviation(dev.DeviationE, dev.DeviationM)
+	r.SyncWord = uint16(regs[SYNC1])<<8 | uint16(regs[SYNC0])
+	r.PreambleBytes = preambleBytes[r.Mdmcfg1.NumPreamble]
+	r.PacketLength = regs[PKTLEN]
+	r.Address = regs[ADDR]
+}
+
+func (r *Report) String() string {
+	var b strings.Builder
+	p := func(format string, args ...interface{}) { fmt.Fprintf(&b, format+"\n", args...) }
+
+	p("Carrier frequency : %.6f MHz (base %.6f MHz, channel %d x %.3f kHz)",
+		r.CarrierHz/1e6, r.FrequencyHz/1e6, r.Channel, r.ChannelSpacingHz/1e3)
+	p("Modulation        : %s", r.Mdmcfg2.Modulation)
+	p("Data rate         : %.2f kBaud", r.DataRate/1e3)
+	p("RX bandwidth      : %.1f kHz (IF %.1f kHz)", r.RxBandwidthHz/1e3, r.IFHz/1e3)
+	if r.Mdmcfg2.Modulation == ModulationOOK {
+		p("Deviation         : %.3f kHz (unused with OOK)", r.DeviationHz/1e3)
+	} else {
+		p("Deviation         : %.3f kHz", r.DeviationHz/1e3)
+	}
+	p("Manchester        : %s", onOff(r.Mdmcfg2.Manchester))
+	p("FEC/interleaving  : %s", onOff(r.Mdmcfg1.FEC))
+	p("DC blocking filter: %s", onOff(!r.Mdmcfg2.DCFilterOff))
+	p("Sync mode         : %s", r.Mdmcfg2.SyncMode)
+	p("Sync word         : 0x%04X", r.SyncWord)
+	p("Preamble          : %d bytes, PQT %d", r.PreambleBytes, r.Pktctrl1.PQT)
+	p("Packet format     : %s", r.Pktctrl0.Format)
+	switch r.Pktctrl0.Length {
+	case LengthFixed:
+		p("Packet length     : fixed, %d bytes", r.PacketLength)
+	case LengthVariable:
+		p("Packet length     : variable, max %d bytes", r.PacketLength)
+	default:
+		p("Packet length     : %s", r.Pktctrl0.Length)
+	}
+	p("CRC               : %s (autoflush %s)", onOff(r.Pktctrl0.CRC), onOff(r.Pktctrl1.CRCAutoflush))
+	p("Whitening         : %s", onOff(r.Pktctrl0.Whitening))
+	p("Append status     : %s", onOff(r.Pktctrl1.AppendStatus))
+	p("Address check     : %s (address 0x%02X)", r.Pktctrl1.AddressCheck, r.Address)
+	p("GDO2              : 0x%02X %s%s", r.Iocfg2.Config, GDOFunction(r.Iocfg2.Config), inverted(r.Iocfg2.Invert))
+	p("GDO1              : 0x%02X %s%s", r.Iocfg1.Config, GDOFunction(r.Iocfg1.Config), inverted(r.Iocfg1.Invert))
+	p("GDO0              : 0x%02X %s%s", r.Iocfg0.Config, GDOFunction(r.Iocfg0.Config), inverted(r.Iocfg0.Invert))
+	p("After RX          : %s", r.Mcsm1.RxOffMode)
+	p("After TX          : %s", r.Mcsm1.TxOffMode)
+	p("CCA mode          : %s", r.Mcsm1.CCAMode)
+	p("Autocalibration   : %s (PO_TIMEOUT %d)", r.Mcsm0.AutoCal, r.Mcsm0.POTimeout)
+	if r.Mcsm2.RxTime == 7 {
+		p("RX timeout        : none")
+	} else {
+		p("RX timeout        : RX_TIME %d (RSSI %s, PQI %s)", r.Mcsm2.RxTime, onOff(r.Mcsm2.RxTimeRSSI), onOff(r.Mcsm2.RxTimeQual))
+	}
+	if r.PATable != nil {
+		idx := r.Frend0.PAPower
+		setting := r.PATable[idx]
+		p("PA setting        : PATABLE[%d] = 0x%02X %s", idx, setting, powerName(setting))
+		if r.Mdmcfg2.Modulation == ModulationOOK && idx > 0 {
+			p("                    OOK '0' uses PATABLE[0] = 0x%02X", r.PATable[0])
+		}
+	} else {
+		p("PA setting        : PATABLE[%d]", r.Frend0.PAPower)
+	}
+	for _, problem := range r.Problems {
+		p("WARNING           : %s", problem)
+	}
+
+	p("")
+	for addr, value := range r.Registers {
+		fmt.Fprintf(&b, "0x%02X %-8s = 0x%02X", addr, registerNames[addr], value)
+		if layout := registerLayout(byte(addr)); len(layout) > 1 {
+			for _, f := range layout {
+				fmt.Fprintf(&b, "  %s=%d", f.name, f.value(value))
+			}
+		}
+		b.WriteByte('\n')
+	}
+	if r.PATable != nil {
+		p("PATABLE       = % X", r.PATable)
+	}
+	return b.String()
+}
+
+// GDOFunction describes the GDOx_CFG signal selection.
+// Read page 62 https://www.ti.com/lit/ds/symlink/cc1101.pdf
+func GDOFunction(cfg byte) string {
+	switch cfg {
+	case 0x00:
+		return "RX FIFO above threshold"
+	case 0x01:
+		return "RX FIFO above threshold or end of packet"
+	case 0x02:
+		return "TX FIFO above threshold"
+	case 0x03:
+		return "TX FIFO full"
+	case 0x04:
+		return "RX FIFO overflow"
+	case 0x05:
+		return "TX FIFO underflow"
+	case 0x06:
+		return "sync word sent/received"
+	case 0x07:
+		return "packet received with CRC OK"
+	case 0x08:
+		return "preamble quality reached"
+	case 0x09:
+		return "clear channel assessment"
+	case 0x0A:
+		return "PLL lock"
+	case 0x0B:
+		return "serial clock"
+	case 0x0C:
+		return "serial synchronous data output"
+	case 0x0D:
+		return "serial data output"
+	case 0x0E:
+		return "carrier sense"
+	case 0x0F:
+		return "CRC OK"
+	case 0x16:
+		return "RX_HARD_DATA[1]"
+	case 0x17:
+		return "RX_HARD_DATA[0]"
+	case 0x1B:
+		return "PA_PD"
+	case 0x1C:
+		return "LNA_PD"
+	case 0x1D:
+		return "RX_SYMBOL_TICK"
+	case 0x24:
+		return "WOR_EVNT0"
+	case 0x25:
+		return "WOR_EVNT1"
+	case 0x26:
+		return "CLK_256"
+	case 0x27:
+		return "CLK_32k"
+	case 0x29:
+		return "CHIP_RDYn"
+	case 0x2B:
+		return "XOSC_STABLE"
+	case 0x2E:
+		return "high impedance"
+	case 0x2F:
+		return "hardwired to 0"
+	}
+	if cfg >= 0x30 && cfg <= 0x3F {
+		divs := [...]string{"1", "1.5", "2", "3", "4", "6", "8", "12", "16", "24", "32", "48", "64", "96", "128", "192"}
+		return "CLK_XOSC/" + divs[cfg-0x30]
+	}
+	return "reserved"
+}
+
+func powerName(setting byte) string {
+	switch setting {
+	case Power_10dBm:
+		return "(+10 dBm)"
+	case Power_7dBm:
+		return "(+7 dBm)"
+	case Power_5dBm:
+		return "(+5 dBm)"
+	case Power_0dBm:
+		return "(0 dBm)"
+	case Power_Neg10dBm:
+		return "(-10 dBm)"
+	case Power_Neg30dBm:
+		return "(-30 dBm)"
+	}
+	return ""
+}
+
+func onOff(v bool) string {
+	if v {
+		return "on"
+	}
+	return "off"
+}
+
+func inverted(v bool) string {
+	if v {
+		return ", inverted"
+	}
+	return ""
+}
@@ -0,0 +1,124 @@
+package cc1101
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseHexDump(t *testing.T) {
+	tests := []struct {
+		text string
+		want []byte
+	}{
+		{"29 2E 06", []byte{0x29, 0x2E, 0x06}},
+		{"0x29, 0X2e,\n0x06", []byte{0x29, 0x2E, 0x06}},
+		{"{0x29; 0x2E}", []byte{0x29, 0x2E}},
+		{"00: 29 2E\n02: 06", []byte{0x29, 0x2E, 0x06}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got, err := ParseHexDump(tt.text)
+		if err != nil {
+			t.Errorf("ParseHexDump(%q): %v", tt.text, err)
+			continue
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("ParseHexDump(%q) = % X, want % X", tt.text, got, tt.want)
+		}
+	}
+	for _, text := range []string{"29 2G", "123", "0x"} {
+		if _, err := ParseHexDump(text); err == nil {
+			t.Errorf("ParseHexDump(%q) succeeded", text)
+		}
+	}
+}
+
+func TestDecodeDump(t *testing.T) {
+	s, err := PresetGFSK868.Config.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dump := append(s.Registers[:], s.PATable[:]...)
+	r, err := DecodeDump(dump)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	near := func(name string, got, want, tolerance float64) {
+		t.Helper()
+		if math.Abs(got-want) > tolerance {
+			t.Errorf("%s = %.1f, want %.1f", name, got, want)
+		}
+	}
+	near("CarrierHz", r.CarrierHz, 868.3e6, 400)
+	near("DataRate", r.DataRate, 38400, 40)
+	near("DeviationHz", r.DeviationHz, 20000, 700)
+	near("RxBandwidthHz", r.RxBandwidthHz, 101562.5, 1)
+	if r.Mdmcfg2.Modulation != ModulationGFSK || r.Mdmcfg2.SyncMode != Sync16of16 {
+		t.Errorf("MDMCFG2 decoded as %+v", r.Mdmcfg2)
+	}
+	if r.SyncWord != 0xD391 || r.PreambleBytes != 4 {
+		t.Errorf("sync word 0x%04X, %d preamble bytes", r.SyncWord, r.PreambleBytes)
+	}
+	if !r.Pktctrl0.CRC || !r.Pktctrl0.Whitening || r.Pktctrl0.Length != LengthVariable {
+		t.Errorf("PKTCTRL0 decoded as %+v", r.Pktctrl0)
+	}
+	if !bytes.Equal(r.PATable, s.PATable[:]) {
+		t.Errorf("PATable = % X, want % X", r.PATable, s.PATable)
+	}
+	if len(r.Problems) != 0 {
+		t.Errorf("Problems = %q", r.Problems)
+	}
+
+	text := r.String()
+	for _, want := range []string{
+		"Modulation        : GFSK",
+		"Sync word         : 0xD391",
+		"Packet length     : variable, max 255 bytes",
+		"PA setting        : PATABLE[0] = 0xC0 (+10 dBm)",
+		"MDMCFG2  = 0x12  DEM_DCFILT_OFF=0  MOD_FORMAT=1  MANCHESTER_EN=0  SYNC_MODE=2",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("report does not contain %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestDecodeDumpRegistersOnly(t *testing.T) {
+	r, err := DecodeDump(resetDefaults[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.PATable != nil {
+		t.Errorf("PATable = % X without PATABLE bytes in the dump", r.PATable)
+	}
+	if !strings.Contains(r.String(), "PA setting        : PATABLE[0]\n") {
+		t.Errorf("report without PATABLE:\n%s", r.String())
+	}
+}
+
+func TestDecodeDumpProblems(t *testing.T) {
+	dump := resetDefaults
+	dump[FIFOTHR] |= 0x80 // reserved bit
+	dump[MDMCFG2] |= 0x20 // MOD_FORMAT 2, undefined
+	r, err := DecodeDump(dump[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Problems) == 0 {
+		t.Error("no problems reported for an invalid MOD_FORMAT")
+	}
+	if !strings.Contains(r.String(), "WARNING") {
+		t.Error("report does not warn about the invalid MOD_FORMAT")
+	}
+}
+
+func TestDecodeDumpLength(t *testing.T) {
+	for _, n := range []int{0, CFG_REGISTER - 1, CFG_REGISTER + 1, CFG_REGISTER + PATABLE_SIZE + 1} {
+		if _, err := DecodeDump(make([]byte, n)); err == nil {
+			t.Errorf("DecodeDump accepted %d bytes", n)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"cc1101"
+	"errors"
 	"fmt"
 	"machine"
 	"time"
@@ -33,7 +34,7 @@ func main() {
 	}
 
 	// Création du device
-	cc := cc1101.New(spi, csPin.Set, machine.Pin(MISO))
+	cc := cc1101.New(spi, csPin.Set, machine.Pin(MISO).Get)
 
 	fmt.Println("Reset du CC1101...")
 	if err := cc.Reset(); err != nil {
@@ -42,9 +43,11 @@ func main() {
 	time.Sleep(100 * time.Millisecond)
 
 	// Vérification de la connexion
-	version, _ := cc.ReadSingleRegister(cc1101.VERSION)
-	partnum, _ := cc.ReadSingleRegister(cc1101.PARTNUM)
-	fmt.Printf("CC1101 détecté - Part: 0x%02X, Version: 0x%02X\n", partnum, version)
+	chip, err := cc.Identify()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("Détecté : %s\n", chip)
 
 	// Configuration complète OOK
 	fmt.Println("Configuration OOK...")
@@ -76,51 +79,40 @@ func main() {
 	// Passage en TX
 	fmt.Println("=== ÉMISSION CONTINUE (Carrier Wave) ===")
 	fmt.Println("Surveille 433.92 MHz sur ton HackRF...")
-	cc.SetTx()
+	if err := cc.SetTx(); err != nil {
+		panic(err)
+	}
 
 	// Surveillance de l'état
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
 
-	for {
-		select {
-		case <-ticker.C:
-			state, _ := cc.ReadSingleRegister(cc1101.MARCSTATE)
-			marcState := state & cc1101.MARCSTATE_MASK
-			
-			stateStr := getStateString(marcState)
-			fmt.Printf("État: %s (0x%02X) | ", stateStr, marcState)
-			
-			txBytes, _ := cc.ReadSingleRegister(cc1101.TXBYTES)
-			fmt.Printf("TXBYTES: %d\n", txBytes&0x7F)
-
-			if marcState != cc1101.MARCSTATE_TX {
-				fmt.Println("⚠️  Pas en TX, relance...")
-				cc.SpiStrobe(cc1101.SFTX) // Flush TX FIFO
-				time.Sleep(10 * time.Millisecond)
-				cc.SetTx()
+	for range ticker.C {
+		// Un underflow du TX FIFO est corrigé (SFTX) par CheckFIFO
+		if err := cc.CheckFIFO(); errors.Is(err, cc1101.ErrTxUnderflow) {
+			fmt.Println("⚠️  TX FIFO underflow, FIFO vidée")
+		} else if err != nil {
+			fmt.Println("Erreur CheckFIFO:", err)
+			continue
+		}
+
+		state, err := cc.MarcState()
+		if err != nil {
+			fmt.Println("Erreur MARCSTATE:", err)
+			continue
+		}
+		txBytes, _, err := cc.TxBytes()
+		if err != nil {
+			fmt.Println("Erreur TXBYTES:", err)
+			continue
+		}
+		fmt.Printf("État: %s | TXBYTES: %d\n", state, txBytes)
+
+		if state != cc1101.MARCSTATE_TX {
+			fmt.Println("⚠️  Pas en TX, relance...")
+			if err := cc.SetTx(); err != nil {
+				fmt.Println("Erreur SetTx:", err)
 			}
 		}
 	}
 }
-
-func getStateString(state byte) string {
-	switch state {
-	case cc1101.MARCSTATE_SLEEP:
-		return "SLEEP"
-	case cc1101.MARCSTATE_IDLE:
-		return "IDLE"
-	case cc1101.MARCSTATE_FSTXON:
-		return "FSTXON"
-	case cc1101.MARCSTATE_TX:
-		return "TX ✓"
-	case cc1101.MARCSTATE_TX_END:
-		return "TX_END"
-	case cc1101.MARCSTATE_RX:
-		return "RX"
-	case cc1101.MARCSTATE_TX_UNDERFLOW:
-		return "TX_UNDERFLOW ⚠"
-	default:
-		return fmt.Sprintf("UNKNOWN_%d", state)
-	}
-}
\ No newline at end of file
@@ -34,13 +34,14 @@ func main() {
     }
 
     // Création de l'instance du pilote CC1101
-    cc := cc1101.New(spi, csPin.Set, machine.Pin(MISO))
+    cc := cc1101.New(spi, csPin.Set, machine.Pin(MISO).Get)
 
     fmt.Println("Vérification de la connexion avec le CC1101...")
-    if !cc.IsConnected() {
-        panic("CC1101 non trouvé. Vérifiez le câblage.")
+    chip, err := cc.Identify()
+    if err != nil {
+        panic(fmt.Sprintf("CC1101 non trouvé, vérifiez le câblage : %v", err))
     }
-    fmt.Println("✅ CC1101 détecté et connecté !")
+    fmt.Printf("✅ %s détecté et connecté !\n", chip)
 
 
     fmt.Println("Configuration du CC1101 pour le mode paquet OOK...")
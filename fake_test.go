@@ -0,0 +1,282 @@
+package cc1101
+
+import (
+	"fmt"
+	"sync"
+)
+
+// fakeChip emulates a CC1101 behind its SPI interface, enough to run the
+// driver without hardware: configuration registers, PATABLE, status
+// registers, strobes and FIFOs. A transmission completes as soon as STX is
+// strobed with data in the TX FIFO.
+type fakeChip struct {
+	mu      sync.Mutex
+	regs    [CFG_REGISTER]byte
+	patable [PATABLE_SIZE]byte
+	state   byte // MARCSTATE
+	txFIFO  []byte
+	rxFIFO  []byte
+	sent    [][]byte // TX FIFO contents at each STX, unless discard is set
+	discard bool
+
+	// fail, if set, is called with the header of each transaction; an
+	// error is returned by Tx, as if the SPI bus failed.
+	fail func(header byte) error
+
+	// onStrobe, if set, is called with c.mu held for each command strobe.
+	// Returning true skips the fake's own handling, so that a test can
+	// script the states the chip goes through.
+	onStrobe func(strobe byte) bool
+
+	bus      *fakeBus
+	selected bool
+	header   byte
+	count    int // bytes exchanged after the header, -1 before it
+}
+
+// fakeBus checks that chip selects on one SPI bus never overlap.
+type fakeBus struct {
+	mu         sync.Mutex
+	selected   int
+	violations []string
+}
+
+func newFakeChip() *fakeChip {
+	return newFakeChipOn(new(fakeBus))
+}
+
+func newFakeChipOn(bus *fakeBus) *fakeChip {
+	c := &fakeChip{
+		bus:    bus,
+		txFIFO: make([]byte, 0, FIFOBUFFER),
+		rxFIFO: make([]byte, 0, FIFOBUFFER),
+	}
+	c.reset()
+	return c
+}
+
+// newFakeDevice returns a Device driving a new fakeChip.
+func newFakeDevice() (*Device, *fakeChip) {
+	c := newFakeChip()
+	return New(c, c.CS, c.MISO), c
+}
+
+func (c *fakeChip) reset() {
+	c.regs = resetDefaults
+	c.patable = resetPATable
+	c.state = MARCSTATE_IDLE
+	c.txFIFO = c.txFIFO[:0]
+	c.rxFIFO = c.rxFIFO[:0]
+}
+
+func (b *fakeBus) violation(format string, args ...any) {
+	b.mu.Lock()
+	b.violations = append(b.violations, fmt.Sprintf(format, args...))
+	b.mu.Unlock()
+}
+
+// Violations returns the chip select errors seen on the bus.
+func (b *fakeBus) Violations() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.violations...)
+}
+
+// CS is the chip select pin, active low.
+func (c *fakeChip) CS(high bool) {
+	c.bus.mu.Lock()
+	if high {
+		c.bus.selected--
+	} else {
+		c.bus.selected++
+	}
+	selected := c.bus.selected
+	c.bus.mu.Unlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case !high && c.selected:
+		c.bus.violation("CS asserted twice")
+	case high && !c.selected:
+		c.bus.violation("CS released while not asserted")
+	case !high && selected > 1:
+		c.bus.violation("CS asserted while another device is selected")
+	}
+	c.selected = !high
+	c.count = -1
+}
+
+// MISO is the MISO pin: the chip is always ready.
+func (c *fakeChip) MISO() bool {
+	return false
+}
+
+func (c *fakeChip) Tx(w, r []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.selected {
+		c.bus.violation("Tx with CS released")
+	}
+	for i, b := range w {
+		if c.count < 0 {
+			if c.fail != nil {
+				if err := c.fail(b); err != nil {
+					return err
+				}
+			}
+			c.header, c.count = b, 0
+			r[i] = c.statusByte()
+			if addr := b & 0x3F; addr >= SRES && addr <= SNOP && b&CC1101_READBURST != CC1101_READBURST {
+				c.strobe(addr)
+			}
+			continue
+		}
+		r[i] = c.access(b)
+		c.count++
+	}
+	return nil
+}
+
+// receive puts bytes in the RX FIFO, overflowing it past 64 bytes.
+func (c *fakeChip) receive(data ...byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := copy(c.rxFIFO[len(c.rxFIFO):cap(c.rxFIFO)], data)
+	c.rxFIFO = c.rxFIFO[:len(c.rxFIFO)+n]
+	if n < len(data) {
+		c.state = MARCSTATE_RX_OVERFLOW
+	}
+}
+
+// packets returns the packets sent so far.
+func (c *fakeChip) packets() [][]byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([][]byte(nil), c.sent...)
+}
+
+func (c *fakeChip) statusByte() byte {
+	var state ChipState
+	switch c.state {
+	case MARCSTATE_RX:
+		state = ChipStateRX
+	case MARCSTATE_TX:
+		state = ChipStateTX
+	case MARCSTATE_FSTXON:
+		state = ChipStateFSTXON
+	case MARCSTATE_RX_OVERFLOW:
+		state = ChipStateRxOverflow
+	case MARCSTATE_TX_UNDERFLOW:
+		state = ChipStateTxUnderflow
+	}
+	fifo := FIFOBUFFER - len(c.txFIFO)
+	if c.header&CC1101_READSINGLE != 0 {
+		fifo = len(c.rxFIFO)
+	}
+	return byte(state)<<4 | byte(min(fifo, 15))
+}
+
+func (c *fakeChip) strobe(s byte) {
+	if c.onStrobe != nil && c.onStrobe(s) {
+		return
+	}
+	switch s {
+	case SRES:
+		c.reset()
+	case SIDLE, SCAL:
+		c.state = MARCSTATE_IDLE
+	case SFSTXON:
+		c.state = MARCSTATE_FSTXON
+	case SRX:
+		c.state = MARCSTATE_RX
+	case SFRX:
+		c.rxFIFO = c.rxFIFO[:0]
+		if c.state == MARCSTATE_RX_OVERFLOW {
+			c.state = MARCSTATE_IDLE
+		}
+	case SFTX:
+		c.txFIFO = c.txFIFO[:0]
+		if c.state == MARCSTATE_TX_UNDERFLOW {
+			c.state = MARCSTATE_IDLE
+		}
+	case STX:
+		if len(c.txFIFO) == 0 {
+			// Preamble is sent until the FIFO is written.
+			c.state = MARCSTATE_TX
+			return
+		}
+		if !c.discard {
+			c.sent = append(c.sent, append([]byte(nil), c.txFIFO...))
+		}
+		c.txFIFO = c.txFIFO[:0]
+		switch OffMode(c.regs[MCSM1] & 0x03) {
+		case OffModeFSTXON:
+			c.state = MARCSTATE_FSTXON
+		case OffModeRX:
+			c.state = MARCSTATE_RX
+		default:
+			c.state = MARCSTATE_IDLE
+		}
+	}
+}
+
+// access reads or writes one byte after the header.
+func (c *fakeChip) access(b byte) byte {
+	read := c.header&CC1101_READSINGLE != 0
+	burst := c.header&CC1101_WRITEBURST != 0
+	addr := c.header & 0x3F
+	switch {
+	case addr < CFG_REGISTER:
+		if burst {
+			addr += byte(c.count)
+		}
+		if addr >= CFG_REGISTER {
+			return 0
+		}
+		if read {
+			return c.regs[addr]
+		}
+		c.regs[addr] = b
+	case addr >= SRES && addr <= SNOP:
+		return c.status(addr | CC1101_READBURST)
+	case addr == PATABLE:
+		i := c.count % PATABLE_SIZE
+		if read {
+			return c.patable[i]
+		}
+		c.patable[i] = b
+	case read:
+		if len(c.rxFIFO) == 0 {
+			return 0
+		}
+		v := c.rxFIFO[0]
+		c.rxFIFO = c.rxFIFO[:copy(c.rxFIFO, c.rxFIFO[1:])]
+		return v
+	case len(c.txFIFO) < cap(c.txFIFO):
+		c.txFIFO = append(c.txFIFO, b)
+	}
+	return 0
+}
+
+func (c *fakeChip) status(addr byte) byte {
+	switch addr {
+	case VERSION:
+		return 0x14
+	case MARCSTATE:
+		return c.state
+	case TXBYTES:
+		if c.state == MARCSTATE_TX_UNDERFLOW {
+			return 0x80 | byte(len(c.txFIFO))
+		}
+		return byte(len(c.txFIFO))
+	case RXBYTES:
+		if c.state == MARCSTATE_RX_OVERFLOW {
+			return 0x80 | byte(len(c.rxFIFO))
+		}
+		return byte(len(c.rxFIFO))
+	case RSSI, LQI:
+		return 0x80
+	}
+	return 0
+}
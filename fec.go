@@ -0,0 +1,159 @@
+package cc1101
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Forward error correction as done by the chip when MDMCFG1 FEC_EN is set:
+// a rate 1/2, constraint length 4 convolutional code followed by 4x4
+// interleaving of the 2-bit code symbols. The implementation follows TI
+// design note DN504 and is bit-exact with the hardware, so raw bitstreams
+// captured with FEC enabled can be decoded offline.
+//
+// The encoder input is the packet as the packet handler sends it after the
+// sync word: length byte (variable length only), address, payload and CRC.
+// FEC is only supported by the chip in fixed length mode.
+
+// Code symbol for each value of the 4 most recent input bits.
+var fecEncodeTable = [16]byte{0, 3, 1, 2, 3, 0, 2, 1, 3, 0, 2, 1, 0, 3, 1, 2}
+
+// Appended to the data to drive the encoder back to a known state.
+const fecTerminator = 0x0B
+
+// EnableFEC turns on FEC and interleaving. The chip only supports FEC with
+// the FIFOs in fixed length mode, so the packet length mode is switched to
+// fixed; set the length with SetPacketLength.
+func (d *Device) EnableFEC() error {
+	_, pktctrl0 := d.packetConfig()
+	if pktctrl0.Format != FormatNormal {
+		return fmt.Errorf("FEC requires the normal packet format, not %s", pktctrl0.Format)
+	}
+	if err := d.setPktctrl0(func(p *Pktctrl0) { p.Length = LengthFixed }); err != nil {
+		return err
+	}
+	var m Mdmcfg1
+	return d.modify(&m, func() { m.FEC = true })
+}
+
+func (d *Device) DisableFEC() error {
+	var m Mdmcfg1
+	return d.modify(&m, func() { m.FEC = false })
+}
+
+// fecEnabled reports whether FEC_EN was last written set.
+func (d *Device) fecEnabled() bool {
+	return d.shadow(MDMCFG1)&0x80 != 0
+}
+
+// FECEncodedLen is the number of bytes sent on the air for n data bytes.
+func FECEncodedLen(n int) int {
+	return 4 * (n/2 + 1)
+}
+
+// FECEncode encodes and interleaves data.
+func FECEncode(data []byte) []byte {
+	num := 2 * (len(data)/2 + 1)
+	input := make([]byte, num)
+	copy(input, data)
+	for i := len(data); i < num; i++ {
+		input[i] = fecTerminator
+	}
+
+	coded := make([]byte, 2*num)
+	var reg uint16
+	for i, b := range input {
+		reg = reg&0x700 | uint16(b)
+		var out uint16
+		for j := 0; j < 8; j++ {
+			out = out<<2 | uint16(fecEncodeTable[reg>>7])
+			reg = (reg << 1) & 0x7FF
+		}
+		coded[2*i] = byte(out >> 8)
+		coded[2*i+1] = byte(out)
+	}
+
+	for i := 0; i < len(coded); i += 4 {
+		interleave(coded[i : i+4])
+	}
+	return coded
+}
+
+// FECDecode deinterleaves and Viterbi decodes coded, the FECEncodedLen(n)
+// bytes received for n data bytes. It returns the data and the number of
+// code bits that had to be corrected.
+func FECDecode(coded []byte, n int) ([]byte, int, error) {
+	if n < 0 || len(coded) != FECEncodedLen(n) {
+		return nil, 0, fmt.Errorf("FEC block has %d bytes, want %d for %d data bytes", len(coded), FECEncodedLen(n), n)
+	}
+	symbols := make([]byte, len(coded))
+	copy(symbols, coded)
+	for i := 0; i < len(symbols); i += 4 {
+		deinterleave(symbols[i : i+4])
+	}
+
+	// Viterbi decoder over the 8 encoder states (the 3 previous input
+	// bits). Each step keeps, for every state, the surviving path metric
+	// and the input bit that led to it.
+	steps := 4 * len(symbols)
+	var metric [8]int
+	for s := 1; s < 8; s++ {
+		metric[s] = 2*steps + 1 // unreachable: the encoder starts in state 0
+	}
+	type survivor struct{ prev, bit byte }
+	trellis := make([][8]survivor, steps)
+	for t := 0; t < steps; t++ {
+		sym := symbols[t/4] >> (6 - 2*(t%4)) & 0x03
+		var next [8]int
+		for s := range next {
+			next[s] = -1
+		}
+		for s := byte(0); s < 8; s++ {
+			for b := byte(0); b < 2; b++ {
+				idx := s<<1 | b
+				m := metric[s] + bits.OnesCount8(fecEncodeTable[idx]^sym)
+				ns := idx & 0x07
+				if next[ns] < 0 || m < next[ns] {
+					next[ns] = m
+					trellis[t][ns] = survivor{s, b}
+				}
+			}
+		}
+		metric = next
+	}
+
+	best := 0
+	for s := 1; s < 8; s++ {
+		if metric[s] < metric[best] {
+			best = s
+		}
+	}
+	decoded := make([]byte, steps/8)
+	state := byte(best)
+	for t := steps - 1; t >= 0; t-- {
+		sv := trellis[t][state]
+		decoded[t/8] |= sv.bit << (7 - t%8)
+		state = sv.prev
+	}
+	return decoded[:n], metric[best], nil
+}
+
+// interleave reorders the 16 code symbols of a 4-byte block in place.
+func interleave(block []byte) {
+	var out uint32
+	for j := 0; j < 16; j++ {
+		out = out<<2 | uint32(block[^j&0x03]>>(2*(j>>2))&0x03)
+	}
+	block[0], block[1], block[2], block[3] = byte(out>>24), byte(out>>16), byte(out>>8), byte(out)
+}
+
+// deinterleave undoes interleave.
+func deinterleave(block []byte) {
+	in := uint32(block[0])<<24 | uint32(block[1])<<16 | uint32(block[2])<<8 | uint32(block[3])
+	var out [4]byte
+	for j := 0; j < 16; j++ {
+		sym := byte(in>>(2*(15-j))) & 0x03
+		out[^j&0x03] |= sym << (2 * (j >> 2))
+	}
+	copy(block, out[:])
+}
@@ -0,0 +1,138 @@
+package cc1101
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+// dn504Encode is the FEC encoder and interleaver of TI design note DN504,
+// ported statement by statement from its C listing, as the reference for
+// FECEncode.
+func dn504Encode(data []byte) []byte {
+	encodeTable := [16]byte{
+		0, 3, 1, 2,
+		3, 0, 2, 1,
+		3, 0, 2, 1,
+		0, 3, 1, 2,
+	}
+	inputNum := len(data)
+	input := make([]byte, inputNum+2)
+	copy(input, data)
+
+	// Append Trellis Terminator
+	input[inputNum] = 0x0B
+	input[inputNum+1] = 0x0B
+	fecNum := 2 * ((inputNum / 2) + 1)
+
+	// FEC encode
+	fec := make([]byte, 2*fecNum)
+	fecReg := 0
+	for i := 0; i < fecNum; i++ {
+		fecReg = (fecReg & 0x700) | int(input[i]&0xFF)
+		fecOutput := 0
+		for j := 0; j < 8; j++ {
+			fecOutput = (fecOutput << 2) | int(encodeTable[fecReg>>7])
+			fecReg = (fecReg << 1) & 0x7FF
+		}
+		fec[i*2] = byte(fecOutput >> 8)
+		fec[i*2+1] = byte(fecOutput & 0xFF)
+	}
+
+	// Perform interleaving
+	interleaved := make([]byte, 2*fecNum)
+	for i := 0; i < fecNum*2; i += 4 {
+		intOutput := uint32(0)
+		for j := 0; j < 4*4; j++ {
+			intOutput = (intOutput << 2) | uint32((fec[i+(^j&0x03)]>>(2*((j&0x0C)>>2)))&0x03)
+		}
+		interleaved[i] = byte(intOutput >> 24)
+		interleaved[i+1] = byte(intOutput >> 16)
+		interleaved[i+2] = byte(intOutput >> 8)
+		interleaved[i+3] = byte(intOutput)
+	}
+	return interleaved
+}
+
+func TestFECEncodeDN504(t *testing.T) {
+	// The DN504 example packet: length byte, three data bytes and CRC.
+	example := AppendCRC16([]byte{0x03, 0x01, 0x02, 0x03})
+	inputs := [][]byte{
+		example,
+		{},
+		{0x00},
+		{0xFF},
+		{0x0B, 0x0B},
+		[]byte("odd length"),
+		bytes.Repeat([]byte{0x55, 0xAA}, 31),
+	}
+	for _, data := range inputs {
+		got, want := FECEncode(data), dn504Encode(data)
+		if !bytes.Equal(got, want) {
+			t.Errorf("FECEncode(% X)\n = % X\nwant % X", data, got, want)
+		}
+		if len(got) != FECEncodedLen(len(data)) {
+			t.Errorf("FECEncode(% X) has %d bytes, FECEncodedLen says %d", data, len(got), FECEncodedLen(len(data)))
+		}
+	}
+}
+
+func TestInterleave(t *testing.T) {
+	for _, block := range [][]byte{{0x00, 0x00, 0x00, 0x00}, {0x1B, 0xE4, 0x4E, 0xB1}, {0x12, 0x34, 0x56, 0x78}} {
+		b := append([]byte(nil), block...)
+		interleave(b)
+		deinterleave(b)
+		if !bytes.Equal(b, block) {
+			t.Errorf("deinterleave(interleave(% X)) = % X", block, b)
+		}
+	}
+}
+
+func TestFECRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for n := 0; n <= FIFOBUFFER; n++ {
+		data := make([]byte, n)
+		rng.Read(data)
+		decoded, corrected, err := FECDecode(FECEncode(data), n)
+		if err != nil {
+			t.Fatalf("%d bytes: %v", n, err)
+		}
+		if !bytes.Equal(decoded, data) {
+			t.Errorf("%d bytes: decoded % X, want % X", n, decoded, data)
+		}
+		if corrected != 0 {
+			t.Errorf("%d bytes: %d bits corrected on an error-free block", n, corrected)
+		}
+	}
+}
+
+func TestFECDecodeCorrectsErrors(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	data := make([]byte, 30)
+	rng.Read(data)
+	coded := FECEncode(data)
+
+	// One bit error in every 4 bytes, spread out enough for the code to
+	// correct them all.
+	flipped := 0
+	for i := 0; i < len(coded); i += 4 {
+		coded[i+rng.Intn(4)] ^= 1 << rng.Intn(8)
+		flipped++
+	}
+	decoded, corrected, err := FECDecode(coded, len(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded, data) {
+		t.Errorf("decoded % X, want % X", decoded, data)
+	}
+	if corrected != flipped {
+		t.Errorf("%d bits corrected, want %d", corrected, flipped)
+	}
+}
+
+func TestFECDecodeLength(t *testing.T) {
+	if _, _, err := FECDecode(make([]byte, 7), 2); err == nil {
+		t.Error("FECDecode accepted a block of the wrong length")
+	}
+}
@@ -0,0 +1,92 @@
+package cc1101
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FIFO error supervision. The chip stops in RXFIFO_OVERFLOW or
+// TXFIFO_UNDERFLOW until the FIFO is flushed; SendData, ReceiveData and
+// CheckFIFO detect these states, flush the FIFO, move the chip to the state
+// configured in MCSM1 and return one of the errors below.
+
+var (
+	ErrRxOverflow  = errors.New("RX FIFO overflow")
+	ErrTxUnderflow = errors.New("TX FIFO underflow")
+)
+
+// FIFOStats counts the FIFO errors recovered since New.
+type FIFOStats struct {
+	RxOverflows  uint32
+	TxUnderflows uint32
+}
+
+// FIFOStats returns the FIFO error counters.
+func (d *Device) FIFOStats() FIFOStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.fifoStats
+}
+
+// CheckFIFO reads the status byte and recovers from a FIFO overflow or
+// underflow. Its STATE bits report both error states, so this costs a
+// single SNOP rather than the repeated MARCSTATE reads of the errata
+// workaround. It returns ErrRxOverflow or ErrTxUnderflow after a recovery
+// and nil when the chip is in any other state.
+func (d *Device) CheckFIFO() error {
+	status, err := d.Status()
+	if err != nil {
+		return err
+	}
+	switch status.State() {
+	case ChipStateRxOverflow:
+		return d.recoverRxOverflow()
+	case ChipStateTxUnderflow:
+		return d.recoverTxUnderflow()
+	}
+	return nil
+}
+
+// recoverRxOverflow flushes the RX FIFO and moves the chip to RXOFF_MODE,
+// staying in IDLE if that is TX.
+func (d *Device) recoverRxOverflow() error {
+	d.mu.Lock()
+	d.fifoStats.RxOverflows++
+	d.mu.Unlock()
+	var m Mcsm1
+	m.Decode(d.shadow(MCSM1))
+	return d.recoverFIFO(ErrRxOverflow, SFRX, m.RxOffMode)
+}
+
+// recoverTxUnderflow flushes the TX FIFO and moves the chip to TXOFF_MODE,
+// staying in IDLE if that is TX.
+func (d *Device) recoverTxUnderflow() error {
+	d.mu.Lock()
+	d.fifoStats.TxUnderflows++
+	d.mu.Unlock()
+	var m Mcsm1
+	m.Decode(d.shadow(MCSM1))
+	return d.recoverFIFO(ErrTxUnderflow, SFTX, m.TxOffMode)
+}
+
+func (d *Device) recoverFIFO(fifoErr error, flush byte, offMode OffMode) error {
+	// SIDLE then the flush strobe also works when the chip already left
+	// the error state.
+	if err := d.SpiStrobe(SIDLE); err != nil {
+		return fmt.Errorf("%w, recovery failed: %w", fifoErr, err)
+	}
+	if err := d.SpiStrobe(flush); err != nil {
+		return fmt.Errorf("%w, recovery failed: %w", fifoErr, err)
+	}
+	target := MarcState(MARCSTATE_IDLE)
+	switch offMode {
+	case OffModeRX:
+		target = MARCSTATE_RX
+	case OffModeFSTXON:
+		target = MARCSTATE_FSTXON
+	}
+	if err := d.Transition(target); err != nil {
+		return fmt.Errorf("%w, recovery failed: %w", fifoErr, err)
+	}
+	return fifoErr
+}
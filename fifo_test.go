@@ -0,0 +1,114 @@
+package cc1101
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckFIFORxOverflow(t *testing.T) {
+	d, chip := newFakeDevice()
+	if err := d.SetRxOffMode(OffModeRX); err != nil {
+		t.Fatal(err)
+	}
+	chip.state = MARCSTATE_RX
+	chip.receive(make([]byte, FIFOBUFFER+1)...)
+
+	if err := d.CheckFIFO(); !errors.Is(err, ErrRxOverflow) {
+		t.Fatalf("CheckFIFO = %v, want ErrRxOverflow", err)
+	}
+	if len(chip.rxFIFO) != 0 {
+		t.Errorf("RX FIFO holds %d bytes after recovery", len(chip.rxFIFO))
+	}
+	if chip.state != MARCSTATE_RX {
+		t.Errorf("chip in %s after recovery, want RXOFF_MODE RX", MarcState(chip.state))
+	}
+	if got := d.FIFOStats(); got != (FIFOStats{RxOverflows: 1}) {
+		t.Errorf("FIFOStats = %+v", got)
+	}
+}
+
+func TestCheckFIFOTxUnderflow(t *testing.T) {
+	d, chip := newFakeDevice()
+	chip.txFIFO = append(chip.txFIFO, 1, 2, 3)
+	chip.state = MARCSTATE_TX_UNDERFLOW
+
+	if err := d.CheckFIFO(); !errors.Is(err, ErrTxUnderflow) {
+		t.Fatalf("CheckFIFO = %v, want ErrTxUnderflow", err)
+	}
+	if len(chip.txFIFO) != 0 {
+		t.Errorf("TX FIFO holds %d bytes after recovery", len(chip.txFIFO))
+	}
+	if chip.state != MARCSTATE_IDLE {
+		t.Errorf("chip in %s after recovery, want IDLE", MarcState(chip.state))
+	}
+	if got := d.FIFOStats(); got != (FIFOStats{TxUnderflows: 1}) {
+		t.Errorf("FIFOStats = %+v", got)
+	}
+}
+
+// Without a FIFO error, CheckFIFO costs one SNOP and changes nothing.
+func TestCheckFIFOClean(t *testing.T) {
+	d, chip := newFakeDevice()
+	chip.state = MARCSTATE_RX
+	rec := NewRecorder(0)
+	d.SetTracer(rec)
+	if err := d.CheckFIFO(); err != nil {
+		t.Fatal(err)
+	}
+	if tr := rec.Transactions(); len(tr) != 1 || tr[0].Name() != "SNOP" {
+		t.Errorf("CheckFIFO made %d transactions:\n%s", len(tr), rec)
+	}
+	if chip.state != MARCSTATE_RX || d.FIFOStats() != (FIFOStats{}) {
+		t.Errorf("chip in %s, FIFOStats %+v", MarcState(chip.state), d.FIFOStats())
+	}
+}
+
+// A FIFO error left by an earlier operation is counted and the packet
+// still sent.
+func TestSendDataAfterOverflow(t *testing.T) {
+	d, chip := newFakeDevice()
+	chip.state = MARCSTATE_RX
+	chip.receive(make([]byte, FIFOBUFFER+1)...)
+	if err := d.SendData([]byte{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	if len(chip.packets()) != 1 {
+		t.Error("packet not sent")
+	}
+	if got := d.FIFOStats(); got != (FIFOStats{RxOverflows: 1}) {
+		t.Errorf("FIFOStats = %+v", got)
+	}
+}
+
+func TestSendDataUnderflow(t *testing.T) {
+	d, chip := newFakeDevice()
+	chip.onStrobe = func(s byte) bool {
+		if s != STX {
+			return false
+		}
+		chip.txFIFO = chip.txFIFO[:0]
+		chip.state = MARCSTATE_TX_UNDERFLOW
+		return true
+	}
+	if err := d.SendData([]byte{1, 2, 3}); !errors.Is(err, ErrTxUnderflow) {
+		t.Fatalf("SendData = %v, want ErrTxUnderflow", err)
+	}
+	if got := d.FIFOStats(); got != (FIFOStats{TxUnderflows: 1}) {
+		t.Errorf("FIFOStats = %+v", got)
+	}
+	if chip.state != MARCSTATE_IDLE {
+		t.Errorf("chip in %s after recovery, want IDLE", MarcState(chip.state))
+	}
+}
+
+func TestReceiveDataOverflow(t *testing.T) {
+	d, chip := newFakeDevice()
+	chip.state = MARCSTATE_RX
+	chip.receive(make([]byte, FIFOBUFFER+1)...)
+	if _, err := d.ReceiveData(); !errors.Is(err, ErrRxOverflow) {
+		t.Fatalf("ReceiveData = %v, want ErrRxOverflow", err)
+	}
+	if got := d.FIFOStats(); got != (FIFOStats{RxOverflows: 1}) {
+		t.Errorf("FIFOStats = %+v", got)
+	}
+}
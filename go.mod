@@ -1,3 +1,5 @@
 module cc1101
 
 go 1.22.4
+
+require gopkg.in/yaml.v3 v3.0.1
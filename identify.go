@@ -0,0 +1,154 @@
+package cc1101
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ChipModel is the radio identified from PARTNUM and VERSION.
+type ChipModel byte
+
+const (
+	ChipUnknown ChipModel = iota
+	ChipCC1101
+	ChipCC110L // CC110L, CC113L and CC115L, which share PARTNUM/VERSION
+	ChipCC1100
+	ChipCC2500
+)
+
+func (m ChipModel) String() string {
+	switch m {
+	case ChipCC1101:
+		return "CC1101"
+	case ChipCC110L:
+		return "CC110L/CC113L/CC115L"
+	case ChipCC1100:
+		return "CC1100"
+	case ChipCC2500:
+		return "CC2500"
+	}
+	return "unknown"
+}
+
+// ChipInfo is the result of Identify.
+type ChipInfo struct {
+	PartNum byte
+	Version byte
+	Model   ChipModel
+}
+
+func (c ChipInfo) String() string {
+	return fmt.Sprintf("%s (PARTNUM 0x%02X, VERSION 0x%02X)", c.Model, c.PartNum, c.Version)
+}
+
+var (
+	// ErrNoChip is returned when PARTNUM and VERSION read as all zeros or
+	// all ones, or the chip never signals ready on MISO, as with a
+	// disconnected or unpowered module.
+	ErrNoChip = errors.New("no chip responding")
+
+	// ErrUnknownChip is returned for a PARTNUM/VERSION pair not listed in
+	// the datasheets.
+	ErrUnknownChip = errors.New("unknown chip")
+
+	// ErrSelfTest is returned when a value written to a register does not
+	// read back.
+	ErrSelfTest = errors.New("register readback failed")
+)
+
+// Patterns written to PKTLEN by the self-test, checking every data line.
+var selfTestPatterns = [...]byte{0xA5, 0x5A}
+
+// Identify reads PARTNUM and VERSION, identifies the chip and checks that
+// registers can be written and read back, using PKTLEN and restoring its
+// value afterwards. The write test only runs with the chip in IDLE, as
+// changing PKTLEN could corrupt a packet being sent or received; in other
+// states Identify only reads. The returned info is valid whenever PARTNUM
+// and VERSION could be read, even when the error is not nil.
+func (d *Device) Identify() (ChipInfo, error) {
+	info, err := d.identify()
+	if err != nil {
+		return info, err
+	}
+	state, err := d.MarcState()
+	if err != nil {
+		return info, fmt.Errorf("reading MARCSTATE: %w", err)
+	}
+	if state != MARCSTATE_IDLE {
+		return info, nil
+	}
+	return info, d.selfTest()
+}
+
+// identify is the read-only part of Identify.
+func (d *Device) identify() (ChipInfo, error) {
+	var info ChipInfo
+	var err error
+	if info.PartNum, err = d.ReadStatusRegister(PARTNUM); err != nil {
+		if errors.Is(err, ErrChipNotReady) {
+			return info, fmt.Errorf("%w: %w", ErrNoChip, err)
+		}
+		return info, fmt.Errorf("reading PARTNUM: %w", err)
+	}
+	if info.Version, err = d.ReadStatusRegister(VERSION); err != nil {
+		return info, fmt.Errorf("reading VERSION: %w", err)
+	}
+	if info.PartNum == info.Version && (info.PartNum == 0x00 || info.PartNum == 0xFF) {
+		return info, fmt.Errorf("%w: PARTNUM and VERSION read 0x%02X", ErrNoChip, info.PartNum)
+	}
+	info.Model = chipModel(info.PartNum, info.Version)
+	if info.Model == ChipUnknown {
+		return info, fmt.Errorf("%w: %s", ErrUnknownChip, info)
+	}
+	return info, nil
+}
+
+func chipModel(partnum, version byte) ChipModel {
+	switch {
+	case partnum == 0x80 && version == 0x03:
+		return ChipCC2500
+	case partnum != 0x00:
+		return ChipUnknown
+	}
+	switch version {
+	case 0x04, 0x14, 0x17:
+		return ChipCC1101
+	case 0x07:
+		return ChipCC110L
+	case 0x03:
+		return ChipCC1100
+	}
+	return ChipUnknown
+}
+
+// selfTest writes test patterns to PKTLEN, reads them back and restores the
+// previous value.
+func (d *Device) selfTest() error {
+	d.cfgMu.Lock()
+	defer d.cfgMu.Unlock()
+
+	saved, err := d.ReadSingleRegister(PKTLEN)
+	if err != nil {
+		return fmt.Errorf("self-test: %w", err)
+	}
+	for _, pattern := range selfTestPatterns {
+		if err = d.WriteSingleRegister(PKTLEN, pattern); err != nil {
+			break
+		}
+		var got byte
+		if got, err = d.ReadSingleRegister(PKTLEN); err != nil {
+			break
+		}
+		if got != pattern {
+			err = fmt.Errorf("%w: wrote 0x%02X to PKTLEN, read 0x%02X", ErrSelfTest, pattern, got)
+			break
+		}
+	}
+	if restoreErr := d.WriteSingleRegister(PKTLEN, saved); err == nil && restoreErr != nil {
+		err = restoreErr
+	}
+	if err != nil {
+		return fmt.Errorf("self-test: %w", err)
+	}
+	return nil
+}
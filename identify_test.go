@@ -0,0 +1,119 @@
+package cc1101
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestChipModel(t *testing.T) {
+	tests := []struct {
+		partnum, version byte
+		want             ChipModel
+	}{
+		{0x00, 0x04, ChipCC1101},
+		{0x00, 0x14, ChipCC1101},
+		{0x00, 0x17, ChipCC1101},
+		{0x00, 0x07, ChipCC110L},
+		{0x00, 0x03, ChipCC1100},
+		{0x80, 0x03, ChipCC2500},
+		{0x80, 0x14, ChipUnknown},
+		{0x00, 0x42, ChipUnknown},
+	}
+	for _, tt := range tests {
+		if got := chipModel(tt.partnum, tt.version); got != tt.want {
+			t.Errorf("chipModel(0x%02X, 0x%02X) = %s, want %s", tt.partnum, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestIdentify(t *testing.T) {
+	d, chip := newFakeDevice()
+	chip.regs[PKTLEN] = 0x3D
+	info, err := d.Identify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info != (ChipInfo{PartNum: 0x00, Version: 0x14, Model: ChipCC1101}) {
+		t.Errorf("Identify = %+v", info)
+	}
+	if chip.regs[PKTLEN] != 0x3D {
+		t.Errorf("PKTLEN = 0x%02X after the self-test, want 0x3D", chip.regs[PKTLEN])
+	}
+}
+
+// stuckBitBus is a fake chip whose data line 5 reads as 0 on register
+// writes.
+type stuckBitBus struct {
+	*fakeChip
+}
+
+func (b stuckBitBus) Tx(w, r []byte) error {
+	if len(w) > 1 && w[0] == PKTLEN {
+		w = append([]byte{w[0]}, w[1]&^0x20)
+	}
+	return b.fakeChip.Tx(w, r)
+}
+
+func TestSelfTestReadback(t *testing.T) {
+	chip := newFakeChip()
+	d := New(stuckBitBus{chip}, chip.CS, chip.MISO)
+	if err := d.selfTest(); !errors.Is(err, ErrSelfTest) {
+		t.Errorf("selfTest = %v, want ErrSelfTest", err)
+	}
+}
+
+// Outside IDLE, Identify and IsConnected must not write PKTLEN under a
+// packet being received.
+func TestIdentifyReadOnly(t *testing.T) {
+	d, chip := newFakeDevice()
+	chip.state = MARCSTATE_RX
+	rec := NewRecorder(0)
+	d.SetTracer(rec)
+	if _, err := d.Identify(); err != nil {
+		t.Fatal(err)
+	}
+	chip.state = MARCSTATE_IDLE
+	if !d.IsConnected() {
+		t.Error("IsConnected = false")
+	}
+	for _, tr := range rec.Transactions() {
+		if op := tr.Op(); op == "write" || op == "write burst" {
+			t.Errorf("register written: %s", &tr)
+		}
+	}
+}
+
+func TestIdentifyNoChip(t *testing.T) {
+	tests := map[string]func() (*Device, *fakeChip){
+		"MISO high": func() (*Device, *fakeChip) {
+			c := newFakeChip()
+			return New(c, c.CS, func() bool { return true }), c
+		},
+		"all ones": func() (*Device, *fakeChip) {
+			c := newFakeChip()
+			return New(allOnesBus{c}, c.CS, c.MISO), c
+		},
+	}
+	for name, newDevice := range tests {
+		d, _ := newDevice()
+		if _, err := d.Identify(); !errors.Is(err, ErrNoChip) {
+			t.Errorf("%s: Identify = %v, want ErrNoChip", name, err)
+		}
+		if d.IsConnected() {
+			t.Errorf("%s: IsConnected = true", name)
+		}
+	}
+}
+
+// allOnesBus reads 0xFF for every byte, as with MISO pulled up and no chip
+// driving it.
+type allOnesBus struct {
+	*fakeChip
+}
+
+func (b allOnesBus) Tx(w, r []byte) error {
+	for i := range r {
+		r[i] = 0xFF
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package cc1101
+
+import "sync"
+
+// A Device can be used from several goroutines. Each SPI transaction is
+// atomic, read-modify-write register updates are serialised, and SendData
+// and ReceiveData each run one at a time. Sequences of calls, e.g. a
+// configuration followed by SetRx, are not atomic and need the caller's own
+// locking.
+
+// ShareBus makes the device hold l, in addition to its own lock, for the
+// whole of each SPI transaction, so that other drivers on the same SPI bus
+// that take l too never interleave with it. Call it before the device is
+// used concurrently.
+func (d *Device) ShareBus(l sync.Locker) {
+	d.mu.Lock()
+	d.busLock = l
+	d.mu.Unlock()
+}
+
+// shadow returns the last value written to a configuration register.
+func (d *Device) shadow(addr byte) byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.regs[addr]
+}
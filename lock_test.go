@@ -0,0 +1,90 @@
+package cc1101
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// TestConcurrentUse sends, receives and reconfigures from separate
+// goroutines while another device on the same SPI bus is used too, and
+// checks that chip selects never overlap. Run it with -race.
+func TestConcurrentUse(t *testing.T) {
+	bus := new(fakeBus)
+	chip := newFakeChipOn(bus)
+	d := New(chip, chip.CS, chip.MISO)
+	otherChip := newFakeChipOn(bus)
+	other := New(otherChip, otherChip.CS, otherChip.MISO)
+	var busLock sync.Mutex
+	d.ShareBus(&busLock)
+	other.ShareBus(&busLock)
+
+	const n = 200
+	var wg sync.WaitGroup
+	run := func(name string, fn func(i int) error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				if err := fn(i); err != nil {
+					t.Errorf("%s #%d: %v", name, i, err)
+					return
+				}
+			}
+		}()
+	}
+
+	run("send", func(i int) error {
+		return d.SendData([]byte{byte(i), 1, 2, 3})
+	})
+	var p Packet
+	run("receive", func(i int) error {
+		payload := []byte{byte(i), 4, 5, 6, 7}
+		chip.receive(append(append([]byte{byte(len(payload))}, payload...), 0x80, 0x80)...)
+		if err := d.ReceiveDataInto(&p); err != nil {
+			return err
+		}
+		if !bytes.Equal(p.Data, payload) {
+			return fmt.Errorf("received % X, want % X", p.Data, payload)
+		}
+		return nil
+	})
+	run("configure", func(i int) error {
+		if err := d.SetSyncWord(uint16(i)); err != nil {
+			return err
+		}
+		if err := d.SetPreambleQualityThreshold(byte(i % 8)); err != nil {
+			return err
+		}
+		return d.SetTxPower(Power_0dBm)
+	})
+	run("status", func(i int) error {
+		_, err := d.MarcState()
+		if errors.Is(err, ErrStatusUnstable) {
+			return nil
+		}
+		return err
+	})
+	run("other device", func(i int) error {
+		if err := other.WriteSingleRegister(ADDR, byte(i)); err != nil {
+			return err
+		}
+		return other.SendData([]byte{byte(i)})
+	})
+	wg.Wait()
+
+	for _, v := range bus.Violations() {
+		t.Error(v)
+	}
+	if sent := len(chip.packets()); sent != n {
+		t.Errorf("%d packets sent, want %d", sent, n)
+	}
+	if sent := len(otherChip.packets()); sent != n {
+		t.Errorf("other device sent %d packets, want %d", sent, n)
+	}
+	if shadow := d.shadowSnapshot(); shadow.Registers != chip.regs || shadow.PATable != chip.patable {
+		t.Errorf("register shadow differs from the chip:\n% X\n% X", shadow.Registers, chip.regs)
+	}
+}
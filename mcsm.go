@@ -0,0 +1,112 @@
+package cc1101
+
+import "fmt"
+
+// Main radio control state machine configuration: MCSM2, MCSM1 and MCSM0.
+
+// SetRxOffMode selects the state entered after a packet has been received:
+// IDLE, FSTXON, TX or stay in RX.
+func (d *Device) SetRxOffMode(mode OffMode) error {
+	return d.setMcsm1(func(m *Mcsm1) { m.RxOffMode = mode & 0x03 })
+}
+
+// SetTxOffMode selects the state entered after a packet has been sent:
+// IDLE, FSTXON, stay in TX or RX. OffModeRX turns the radio around to wait
+// for an acknowledgement without an extra SRX strobe.
+func (d *Device) SetTxOffMode(mode OffMode) error {
+	return d.setMcsm1(func(m *Mcsm1) { m.TxOffMode = mode & 0x03 })
+}
+
+// SetCCAMode selects the clear channel condition required before STX is
+// allowed to enter TX.
+func (d *Device) SetCCAMode(mode CCAMode) error {
+	return d.setMcsm1(func(m *Mcsm1) { m.CCAMode = mode & 0x03 })
+}
+
+// SetAutoCal selects when the frequency synthesizer is calibrated
+// automatically. With AutoCalNever, calibrate with the SCAL strobe.
+func (d *Device) SetAutoCal(mode AutoCal) error {
+	return d.setMcsm0(func(m *Mcsm0) { m.AutoCal = mode & 0x03 })
+}
+
+// SetPOTimeout sets how long the chip waits for the regulated voltage to
+// settle after XOSC start: 1, 16, 64 or 256 expiries of a counter running at
+// XOSC/64 for timeout 0 to 3, about 2.4 us, 39 us, 155 us and 620 us with a
+// 26 MHz crystal. 2 is a safe default when leaving SLEEP.
+func (d *Device) SetPOTimeout(timeout byte) error {
+	if timeout > 3 {
+		return fmt.Errorf("invalid PO_TIMEOUT: %d", timeout)
+	}
+	return d.setMcsm0(func(m *Mcsm0) { m.POTimeout = timeout })
+}
+
+// SetRxTime sets the RX timeout for sync word search, MCSM2 RX_TIME. The
+// timeout depends on the data rate and WOR_RES, see the datasheet table for
+// MCSM2; 7 disables the timeout.
+func (d *Device) SetRxTime(rxTime byte) error {
+	if rxTime > 7 {
+		return fmt.Errorf("invalid RX_TIME: %d", rxTime)
+	}
+	return d.setMcsm2(func(m *Mcsm2) { m.RxTime = rxTime })
+}
+
+// EnableRxTimeRSSI ends RX early when no carrier is sensed, if RX_TIME is
+// not 7.
+func (d *Device) EnableRxTimeRSSI() error {
+	return d.setMcsm2(func(m *Mcsm2) { m.RxTimeRSSI = true })
+}
+
+func (d *Device) DisableRxTimeRSSI() error {
+	return d.setMcsm2(func(m *Mcsm2) { m.RxTimeRSSI = false })
+}
+
+// EnableRxTimeQual keeps RX running at RX_TIME expiry when the preamble
+// quality indicator is set, not only when a sync word has been found.
+func (d *Device) EnableRxTimeQual() error {
+	return d.setMcsm2(func(m *Mcsm2) { m.RxTimeQual = true })
+}
+
+func (d *Device) DisableRxTimeQual() error {
+	return d.setMcsm2(func(m *Mcsm2) { m.RxTimeQual = false })
+}
+
+// GetStateMachineConfig reads MCSM2, MCSM1 and MCSM0.
+func (d *Device) GetStateMachineConfig() (*Mcsm2, *Mcsm1, *Mcsm0, error) {
+	var m2 Mcsm2
+	var m1 Mcsm1
+	var m0 Mcsm0
+	var regs [3]byte
+	if err := d.ReadBurstRegisterInto(MCSM2, regs[:]); err != nil {
+		return nil, nil, nil, err
+	}
+	for i, r := range []Register{&m2, &m1, &m0} {
+		if err := r.Decode(regs[i]); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return &m2, &m1, &m0, nil
+}
+
+func (d *Device) setMcsm2(fn func(m *Mcsm2)) error {
+	var m Mcsm2
+	if err := d.modify(&m, func() { fn(&m) }); err != nil {
+		return fmt.Errorf("Error writing in the register : %v", err)
+	}
+	return nil
+}
+
+func (d *Device) setMcsm1(fn func(m *Mcsm1)) error {
+	var m Mcsm1
+	if err := d.modify(&m, func() { fn(&m) }); err != nil {
+		return fmt.Errorf("Error writing in the register : %v", err)
+	}
+	return nil
+}
+
+func (d *Device) setMcsm0(fn func(m *Mcsm0)) error {
+	var m Mcsm0
+	if err := d.modify(&m, func() { fn(&m) }); err != nil {
+		return fmt.Errorf("Error writing in the register : %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,5 @@
+//go:build !race
+
+package cc1101
+
+const raceEnabled = false
@@ -0,0 +1,37 @@
+package cc1101
+
+import (
+	"math"
+	"testing"
+)
+
+// TestOOKSettingsRates checks the data rate and RX bandwidth documented for
+// the deprecated OOK configurations against their registers.
+func TestOOKSettingsRates(t *testing.T) {
+	tests := []struct {
+		name        string
+		configure   func(d *Device) error
+		dataRate    float64
+		rxBandwidth float64
+	}{
+		{"Configure", (*Device).Configure, 10000, 101e3},
+		{"ConfigureOOKPacket", (*Device).ConfigureOOKPacket, 4800, 58e3},
+	}
+	for _, tt := range tests {
+		d, _ := newFakeDevice()
+		if err := tt.configure(d); err != nil {
+			t.Fatal(err)
+		}
+		s, err := d.Snapshot()
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := DecodeSnapshot(s)
+		if math.Abs(r.DataRate-tt.dataRate) > tt.dataRate/100 {
+			t.Errorf("%s: data rate %.0f baud, documented as %.0f", tt.name, r.DataRate, tt.dataRate)
+		}
+		if math.Abs(r.RxBandwidthHz-tt.rxBandwidth) > tt.rxBandwidth/100 {
+			t.Errorf("%s: RX bandwidth %.0f Hz, documented as %.0f", tt.name, r.RxBandwidthHz, tt.rxBandwidth)
+		}
+	}
+}
@@ -2,46 +2,116 @@
 package cc1101
 
 import (
+    "errors"
     "fmt"
     "time"
 )
 
 
+// SendData sends one packet in the configured length mode: preceded by its
+// length byte in variable length mode, exactly PKTLEN bytes in fixed length
+// mode. In infinite length mode the radio is switched to fixed length for
+// the duration of the packet so that it stops after the last byte instead
+// of underflowing the TX FIFO. With FEC enabled only fixed length is
+// accepted; the chip encodes the FIFO contents on the fly.
 func (d *Device) SendData(packet []byte) error {
+    d.txMu.Lock()
+    defer d.txMu.Unlock()
+    return d.sendData(packet)
+}
 
-    if len(packet) > FIFOBUFFER {
-        return fmt.Errorf("packet too long: %d bytes (max %d)", len(packet), FIFOBUFFER)
+func (d *Device) sendData(packet []byte) (err error) {
+
+    _, pktctrl0 := d.packetConfig()
+    if d.fecEnabled() && pktctrl0.Length != LengthFixed {
+        return fmt.Errorf("FEC requires fixed packet length, configured %s", pktctrl0.Length)
+    }
+    var fifoPayload []byte
+
+    switch pktctrl0.Length {
+    case LengthVariable:
+        if len(packet) > FIFOBUFFER-1 {
+            return fmt.Errorf("packet too long: %d bytes (max %d)", len(packet), FIFOBUFFER-1)
+        }
+        // packet may already be in d.txFIFO (SendDataTo): copy, which
+        // handles the overlap, before writing the length byte.
+        fifoPayload = d.txFIFO[:1+len(packet)]
+        copy(fifoPayload[1:], packet)
+        fifoPayload[0] = byte(len(packet))
+    case LengthFixed:
+        if pktlen := int(d.shadow(PKTLEN)); len(packet) != pktlen {
+            return fmt.Errorf("packet has %d bytes, fixed packet length is %d", len(packet), pktlen)
+        }
+        if len(packet) > FIFOBUFFER {
+            return fmt.Errorf("packet too long: %d bytes (max %d)", len(packet), FIFOBUFFER)
+        }
+        fifoPayload = packet
+    default:
+        if len(packet) == 0 || len(packet) > FIFOBUFFER {
+            return fmt.Errorf("invalid packet size: %d bytes (1 to %d)", len(packet), FIFOBUFFER)
+        }
+        fifoPayload = packet
     }
 
+    // A FIFO error left over from a previous operation is counted and
+    // cleared; the packet is still sent.
+    if err := d.CheckFIFO(); err != nil && !errors.Is(err, ErrRxOverflow) && !errors.Is(err, ErrTxUnderflow) {
+        return err
+    }
     d.SpiStrobe(SIDLE)
     d.SpiStrobe(SFTX)
 
-    fifoPayload := make([]byte, 1+len(packet))
-    fifoPayload[0] = byte(len(packet))
-    copy(fifoPayload[1:], packet)
+    if pktctrl0.Length == LengthInfinite {
+        pktlen := d.shadow(PKTLEN)
+        if err := d.WriteSingleRegister(PKTLEN, byte(len(packet))); err != nil {
+            return err
+        }
+        // Go back to infinite length whatever happens to the packet, and
+        // report it if that fails: the radio would be left in fixed length.
+        defer func() {
+            if restoreErr := d.restoreInfiniteLength(pktlen); restoreErr != nil {
+                err = errors.Join(err, restoreErr)
+            }
+        }()
+        if err := d.SetLengthConfig(LengthFixed); err != nil {
+            return err
+        }
+    }
 
-    err := d.WriteBurstRegister(TXFIFO_SINGLE_BYTE, fifoPayload)
-    if err != nil {
+    if err := d.WriteBurstRegister(TXFIFO_SINGLE_BYTE, fifoPayload); err != nil {
         return fmt.Errorf("failed to write to TX FIFO: %w", err)
     }
 
 
     d.SpiStrobe(STX)
 
+    // The status byte of an SNOP is enough to follow the transmission,
+    // no need to read MARCSTATE.
     for {
-        state, err := d.ReadSingleRegister(MARCSTATE)
+        status, err := d.Status()
         if err != nil {
-            return fmt.Errorf("failed to read MARCSTATE: %w", err)
+            return fmt.Errorf("failed to read status: %w", err)
         }
 
-        currentState := state & MARCSTATE_MASK
-
-        if currentState != MARCSTATE_TX && currentState != MARCSTATE_TX_END {
-            break
+        switch status.State() {
+        case ChipStateTxUnderflow:
+            return d.recoverTxUnderflow()
+        case ChipStateTX, ChipStateCalibrate, ChipStateSettling:
+            time.Sleep(1 * time.Millisecond)
+            continue
         }
-
-        time.Sleep(1 * time.Millisecond)
+        return nil
     }
+}
 
+// restoreInfiniteLength undoes the switch to fixed length made by sendData
+// for a packet in infinite length mode.
+func (d *Device) restoreInfiniteLength(pktlen byte) error {
+    if err := d.SetLengthConfig(LengthInfinite); err != nil {
+        return fmt.Errorf("failed to restore infinite packet length: %w", err)
+    }
+    if err := d.WriteSingleRegister(PKTLEN, pktlen); err != nil {
+        return fmt.Errorf("failed to restore PKTLEN: %w", err)
+    }
     return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,53 @@
+package cc1101
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSendDataInfiniteLength(t *testing.T) {
+	d, chip := newFakeDevice()
+	if err := d.SetLengthConfig(LengthInfinite); err != nil {
+		t.Fatal(err)
+	}
+	packet := []byte{1, 2, 3, 4, 5}
+	if err := d.SendData(packet); err != nil {
+		t.Fatal(err)
+	}
+	if sent := chip.packets(); len(sent) != 1 || !bytes.Equal(sent[0], packet) {
+		t.Errorf("TX FIFO = % X, want % X", sent, packet)
+	}
+	if got := LengthConfig(chip.regs[PKTCTRL0] & 0x03); got != LengthInfinite {
+		t.Errorf("LENGTH_CONFIG = %s after sending, want %s", got, LengthInfinite)
+	}
+	if got := chip.regs[PKTLEN]; got != resetDefaults[PKTLEN] {
+		t.Errorf("PKTLEN = %d after sending, want %d", got, resetDefaults[PKTLEN])
+	}
+}
+
+func TestSendDataInfiniteLengthRestoreError(t *testing.T) {
+	d, chip := newFakeDevice()
+	if err := d.SetLengthConfig(LengthInfinite); err != nil {
+		t.Fatal(err)
+	}
+	// Let the switch to fixed length through, fail the switch back.
+	errBus := errors.New("bus error")
+	writes := 0
+	chip.fail = func(header byte) error {
+		if header == PKTCTRL0 {
+			if writes++; writes > 1 {
+				return errBus
+			}
+		}
+		return nil
+	}
+	err := d.SendData([]byte{1, 2, 3})
+	if err == nil || !strings.Contains(err.Error(), "restore infinite packet length") {
+		t.Fatalf("SendData = %v, want the restore error", err)
+	}
+	if len(chip.packets()) != 1 {
+		t.Error("packet not sent")
+	}
+}
@@ -0,0 +1,117 @@
+package cc1101
+
+import "fmt"
+
+// Packet handler configuration: PKTCTRL1, PKTCTRL0 and PKTLEN.
+
+func (d *Device) EnableCRC() error {
+	return d.setPktctrl0(func(p *Pktctrl0) { p.CRC = true })
+}
+
+func (d *Device) DisableCRC() error {
+	return d.setPktctrl0(func(p *Pktctrl0) { p.CRC = false })
+}
+
+// EnableWhitening XORs the payload and CRC with the PN9 sequence, which
+// avoids long runs of identical bits on the air.
+func (d *Device) EnableWhitening() error {
+	return d.setPktctrl0(func(p *Pktctrl0) { p.Whitening = true })
+}
+
+func (d *Device) DisableWhitening() error {
+	return d.setPktctrl0(func(p *Pktctrl0) { p.Whitening = false })
+}
+
+// SetPacketFormat selects between the FIFOs and the GDO0 serial modes.
+func (d *Device) SetPacketFormat(format PacketFormat) error {
+	if format > FormatAsyncSerial {
+		return fmt.Errorf("invalid packet format: %d", format)
+	}
+	if format != FormatNormal && d.fecEnabled() {
+		return fmt.Errorf("%s is not supported with FEC", format)
+	}
+	return d.setPktctrl0(func(p *Pktctrl0) { p.Format = format })
+}
+
+// SetLengthConfig selects fixed (PKTLEN bytes), variable (length byte
+// first) or infinite packet length. SendData and ReceiveData follow the
+// configured mode. Only fixed length is allowed while FEC is enabled.
+func (d *Device) SetLengthConfig(length LengthConfig) error {
+	if length > LengthInfinite {
+		return fmt.Errorf("invalid length config: %d", length)
+	}
+	if length != LengthFixed && d.fecEnabled() {
+		return fmt.Errorf("%s length is not supported with FEC", length)
+	}
+	return d.setPktctrl0(func(p *Pktctrl0) { p.Length = length })
+}
+
+// GetPacketConfig reads PKTCTRL0.
+func (d *Device) GetPacketConfig() (*Pktctrl0, error) {
+	var p Pktctrl0
+	if err := d.ReadRegister(&p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// EnableCRCAutoflush makes the chip flush packets with a bad CRC from the
+// RX FIFO, so ReceiveData only sees packets that passed the check. It
+// requires appended status bytes and a packet that fits in the RX FIFO.
+func (d *Device) EnableCRCAutoflush() error {
+	return d.setPktctrl1(func(p *Pktctrl1) { p.CRCAutoflush = true })
+}
+
+func (d *Device) DisableCRCAutoflush() error {
+	return d.setPktctrl1(func(p *Pktctrl1) { p.CRCAutoflush = false })
+}
+
+// EnableAppendStatus appends RSSI, LQI and CRC OK to received packets.
+func (d *Device) EnableAppendStatus() error {
+	return d.setPktctrl1(func(p *Pktctrl1) { p.AppendStatus = true })
+}
+
+func (d *Device) DisableAppendStatus() error {
+	return d.setPktctrl1(func(p *Pktctrl1) { p.AppendStatus = false })
+}
+
+// SetPacketLength sets PKTLEN: the packet length in fixed length mode, or
+// the longest accepted packet in variable length mode.
+func (d *Device) SetPacketLength(length byte) error {
+	if length == 0 {
+		return fmt.Errorf("invalid packet length: 0")
+	}
+	return d.WriteSingleRegister(PKTLEN, length)
+}
+
+// GetPacketLength reads PKTLEN.
+func (d *Device) GetPacketLength() (byte, error) {
+	return d.ReadSingleRegister(PKTLEN)
+}
+
+func (d *Device) setPktctrl0(fn func(p *Pktctrl0)) error {
+	var p Pktctrl0
+	if err := d.modify(&p, func() { fn(&p) }); err != nil {
+		return fmt.Errorf("Error writing in the register : %v", err)
+	}
+	return nil
+}
+
+func (d *Device) setPktctrl1(fn func(p *Pktctrl1)) error {
+	var p Pktctrl1
+	if err := d.modify(&p, func() { fn(&p) }); err != nil {
+		return fmt.Errorf("Error writing in the register : %v", err)
+	}
+	return nil
+}
+
+// packetConfig decodes the packet handler settings last written to the chip.
+func (d *Device) packetConfig() (Pktctrl1, Pktctrl0) {
+	var p1 Pktctrl1
+	var p0 Pktctrl0
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	p1.Decode(d.regs[PKTCTRL1])
+	p0.Decode(d.regs[PKTCTRL0])
+	return p1, p0
+}
@@ -0,0 +1,84 @@
+package cc1101
+
+import "fmt"
+
+// Preset is a vetted radio configuration. Description gives the typical
+// sensitivity and current consumption from the datasheet tables for that
+// data rate; the actual figures depend on the board and matching network.
+type Preset struct {
+	Name        string
+	Description string
+	Config      RadioConfig
+}
+
+var (
+	PresetOOK433 = Preset{
+		Name:        "OOK 433.92 MHz 4.8 kBaud",
+		Description: "Remote controls and sensors. About -106 dBm sensitivity, 16 mA RX, 15-30 mA TX depending on the bit pattern.",
+		Config: RadioConfig{
+			FrequencyHz: 433.92e6, Modulation: ModulationOOK, DataRate: 4800,
+			SyncWord: 0xD391, SyncMode: Sync16of16, PreambleBytes: 4,
+			Length: LengthVariable, CRC: true, TxPower: Power_10dBm,
+		},
+	}
+	PresetGFSK868 = Preset{
+		Name:        "GFSK 868.3 MHz 38.4 kBaud",
+		Description: "General purpose SRD band link. About -104 dBm sensitivity, 16 mA RX, 30 mA TX at +10 dBm.",
+		Config: RadioConfig{
+			FrequencyHz: 868.3e6, Modulation: ModulationGFSK, DataRate: 38400, DeviationHz: 20000,
+			RxBandwidthHz: 100000, SyncWord: 0xD391, SyncMode: Sync16of16, PreambleBytes: 4,
+			Length: LengthVariable, CRC: true, Whitening: true, TxPower: Power_10dBm,
+		},
+	}
+	Preset2FSKLongRange = Preset{
+		Name:        "2-FSK 868.3 MHz 1.2 kBaud long range",
+		Description: "Best sensitivity, about -112 dBm, at the cost of 32x longer air time than 38.4 kBaud. 15 mA RX, 30 mA TX at +10 dBm.",
+		Config: RadioConfig{
+			FrequencyHz: 868.3e6, Modulation: Modulation2FSK, DataRate: 1200, DeviationHz: 5200,
+			RxBandwidthHz: 58000, SyncWord: 0xD391, SyncMode: Sync30of32, PreambleBytes: 8,
+			Length: LengthVariable, CRC: true, Whitening: true, TxPower: Power_10dBm,
+		},
+	}
+	PresetGFSK250k = Preset{
+		Name:        "GFSK 868.3 MHz 250 kBaud high throughput",
+		Description: "Short range bulk transfer. About -95 dBm sensitivity, 17 mA RX, 30 mA TX at +10 dBm.",
+		Config: RadioConfig{
+			FrequencyHz: 868.3e6, Modulation: ModulationGFSK, DataRate: 250000, DeviationHz: 127000,
+			RxBandwidthHz: 540000, SyncWord: 0xD391, SyncMode: Sync30of32, PreambleBytes: 8,
+			Length: LengthVariable, CRC: true, Whitening: true, TxPower: Power_10dBm,
+		},
+	}
+	PresetMSK500k = Preset{
+		Name:        "MSK 868.3 MHz 500 kBaud",
+		Description: "Highest data rate, shortest range. About -86 dBm sensitivity, 17 mA RX, 30 mA TX at +10 dBm.",
+		Config: RadioConfig{
+			FrequencyHz: 868.3e6, Modulation: ModulationMSK, DataRate: 500000,
+			SyncWord: 0xD391, SyncMode: Sync30of32, PreambleBytes: 8,
+			Length: LengthVariable, CRC: true, Whitening: true, TxPower: Power_10dBm,
+		},
+	}
+)
+
+// Presets lists the built-in presets.
+var Presets = []*Preset{&PresetOOK433, &PresetGFSK868, &Preset2FSKLongRange, &PresetGFSK250k, &PresetMSK500k}
+
+// FindPreset returns the built-in preset with the given name.
+func FindPreset(name string) (*Preset, error) {
+	for _, p := range Presets {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown preset %q", name)
+}
+
+// ApplyPreset resets the chip and programs the preset configuration.
+func (d *Device) ApplyPreset(p *Preset) error {
+	if err := d.Reset(); err != nil {
+		return fmt.Errorf("reset failed: %v", err)
+	}
+	if err := d.ApplyConfig(&p.Config); err != nil {
+		return fmt.Errorf("preset %q: %w", p.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+// Package radioconf stores cc1101.RadioConfig values in JSON or YAML files,
+// in physical units.
+//
+//	version: 1
+//	frequency_mhz: 868.3
+//	modulation: GFSK
+//	data_rate_kbaud: 38.4
+//	deviation_khz: 20
+//	rx_bandwidth_khz: 100
+//	sync_word: "0xD391"
+//	sync_mode: "16/16"
+//	preamble_bytes: 4
+//	length_mode: variable
+//	packet_length: 255
+//	crc: true
+//	whitening: true
+//	tx_power_dbm: 10
+//
+// Unmarshal validates the file through cc1101.RadioConfig.Build, so a file
+// that loads is one the driver can apply; errors name the offending field.
+// The package is meant for host tools and is kept out of the driver so
+// firmware does not link the JSON and YAML decoders.
+package radioconf
+
+import (
+	"bytes"
+	"cc1101"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the file format version written by Marshal.
+const Version = 1
+
+// Format selects the file encoding.
+type Format int
+
+const (
+	JSON Format = iota
+	YAML
+)
+
+// FormatFromPath picks the format from a file extension.
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return JSON, nil
+	case ".yaml", ".yml":
+		return YAML, nil
+	}
+	return 0, fmt.Errorf("%s: unknown configuration file extension", path)
+}
+
+// File is the on-disk form of a radio configuration.
+type File struct {
+	Version        int     `json:"version" yaml:"version"`
+	FrequencyMHz   float64 `json:"frequency_mhz" yaml:"frequency_mhz"`
+	Modulation     string  `json:"modulation" yaml:"modulation"`
+	DataRateKBaud  float64 `json:"data_rate_kbaud" yaml:"data_rate_kbaud"`
+	DeviationKHz   float64 `json:"deviation_khz,omitempty" yaml:"deviation_khz,omitempty"`
+	RxBandwidthKHz float64 `json:"rx_bandwidth_khz,omitempty" yaml:"rx_bandwidth_khz,omitempty"`
+	SyncWord       string  `json:"sync_word" yaml:"sync_word"`
+	SyncMode       string  `json:"sync_mode" yaml:"sync_mode"`
+	PreambleBytes  int     `json:"preamble_bytes,omitempty" yaml:"preamble_bytes,omitempty"`
+	PacketFormat   string  `json:"packet_format,omitempty" yaml:"packet_format,omitempty"`
+	LengthMode     string  `json:"length_mode" yaml:"length_mode"`
+	PacketLength   int     `json:"packet_length" yaml:"packet_length"`
+	CRC            bool    `json:"crc" yaml:"crc"`
+	Whitening      bool    `json:"whitening" yaml:"whitening"`
+	Manchester     bool    `json:"manchester" yaml:"manchester"`
+	FEC            bool    `json:"fec" yaml:"fec"`
+	TxPowerDBm     *int    `json:"tx_power_dbm,omitempty" yaml:"tx_power_dbm,omitempty"`
+}
+
+// FieldError reports the file field that could not be used.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error { return e.Err }
+
+func fieldErr(field, format string, args ...interface{}) error {
+	return &FieldError{Field: field, Err: fmt.Errorf(format, args...)}
+}
+
+// File field for each cc1101.RadioConfig field, to translate ConfigError.
+var configFields = map[string]string{
+	"FrequencyHz":   "frequency_mhz",
+	"Modulation":    "modulation",
+	"DataRate":      "data_rate_kbaud",
+	"DeviationHz":   "deviation_khz",
+	"RxBandwidthHz": "rx_bandwidth_khz",
+	"SyncMode":      "sync_mode",
+	"PreambleBytes": "preamble_bytes",
+	"Format":        "packet_format",
+	"Length":        "length_mode",
+	"PacketLength":  "packet_length",
+	"Manchester":    "manchester",
+	"FEC":           "fec",
+}
+
+var syncModes = map[cc1101.SyncMode]string{
+	cc1101.SyncNone:     "none",
+	cc1101.Sync15of16:   "15/16",
+	cc1101.Sync16of16:   "16/16",
+	cc1101.Sync30of32:   "30/32",
+	cc1101.SyncNoneCS:   "none+cs",
+	cc1101.Sync15of16CS: "15/16+cs",
+	cc1101.Sync16of16CS: "16/16+cs",
+	cc1101.Sync30of32CS: "30/32+cs",
+}
+
+var packetFormats = map[cc1101.PacketFormat]string{
+	cc1101.FormatNormal:      "normal",
+	cc1101.FormatSyncSerial:  "sync-serial",
+	cc1101.FormatRandomTX:    "random-tx",
+	cc1101.FormatAsyncSerial: "async-serial",
+}
+
+var lengthModes = map[cc1101.LengthConfig]string{
+	cc1101.LengthFixed:    "fixed",
+	cc1101.LengthVariable: "variable",
+	cc1101.LengthInfinite: "infinite",
+}
+
+var txPowers = map[int]byte{
+	10:  cc1101.Power_10dBm,
+	7:   cc1101.Power_7dBm,
+	5:   cc1101.Power_5dBm,
+	0:   cc1101.Power_0dBm,
+	-10: cc1101.Power_Neg10dBm,
+	-30: cc1101.Power_Neg30dBm,
+}
+
+func lookup[K comparable](m map[K]string, field, name string) (K, error) {
+	for k, v := range m {
+		if v == name {
+			return k, nil
+		}
+	}
+	var zero K
+	return zero, fieldErr(field, "unknown value %q", name)
+}
+
+// FromConfig converts c to its file form.
+func FromConfig(c *cc1101.RadioConfig) (*File, error) {
+	f := &File{
+		Version:        Version,
+		FrequencyMHz:   round(c.FrequencyHz/1e6, 6),
+		Modulation:     c.Modulation.String(),
+		DataRateKBaud:  round(c.DataRate/1e3, 4),
+		DeviationKHz:   round(c.DeviationHz/1e3, 4),
+		RxBandwidthKHz: round(c.RxBandwidthHz/1e3, 4),
+		SyncWord:       fmt.Sprintf("0x%04X", c.SyncWord),
+		SyncMode:       syncModes[c.SyncMode],
+		PreambleBytes:  c.PreambleBytes,
+		LengthMode:     lengthModes[c.Length],
+		PacketLength:   int(c.PacketLength),
+		CRC:            c.CRC,
+		Whitening:      c.Whitening,
+		Manchester:     c.Manchester,
+		FEC:            c.FEC,
+	}
+	if c.Format != cc1101.FormatNormal {
+		f.PacketFormat = packetFormats[c.Format]
+	}
+	if c.TxPower != 0 {
+		found := false
+		for dbm, setting := range txPowers {
+			if setting == c.TxPower {
+				f.TxPowerDBm, found = &dbm, true
+			}
+		}
+		if !found {
+			return nil, fieldErr("tx_power_dbm", "PATABLE setting 0x%02X has no dBm equivalent", c.TxPower)
+		}
+	}
+	if _, err := f.Config(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Config converts f to a cc1101.RadioConfig and validates it.
+func (f *File) Config() (*cc1101.RadioConfig, error) {
+	switch {
+	case f.Version == 0:
+		return nil, fieldErr("version", "missing")
+	case f.Version > Version:
+		return nil, fieldErr("version", "%d is newer than the supported version %d", f.Version, Version)
+	case f.Version < 0:
+		return nil, fieldErr("version", "invalid version %d", f.Version)
+	}
+
+	c := &cc1101.RadioConfig{
+		FrequencyHz:   f.FrequencyMHz * 1e6,
+		DataRate:      f.DataRateKBaud * 1e3,
+		DeviationHz:   f.DeviationKHz * 1e3,
+		RxBandwidthHz: f.RxBandwidthKHz * 1e3,
+		PreambleBytes: f.PreambleBytes,
+		CRC:           f.CRC,
+		Whitening:     f.Whitening,
+		Manchester:    f.Manchester,
+		FEC:           f.FEC,
+	}
+	var err error
+	if c.Modulation, err = cc1101.ParseModulation(f.Modulation); err != nil {
+		return nil, fieldErr("modulation", "unknown value %q", f.Modulation)
+	}
+	sync, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(f.SyncWord), "0x"), 16, 16)
+	if err != nil {
+		return nil, fieldErr("sync_word", "%q is not a 16-bit hex value", f.SyncWord)
+	}
+	c.SyncWord = uint16(sync)
+	if c.SyncMode, err = lookup(syncModes, "sync_mode", f.SyncMode); err != nil {
+		return nil, err
+	}
+	if f.PacketFormat != "" {
+		if c.Format, err = lookup(packetFormats, "packet_format", f.PacketFormat); err != nil {
+			return nil, err
+		}
+	}
+	if c.Length, err = lookup(lengthModes, "length_mode", f.LengthMode); err != nil {
+		return nil, err
+	}
+	if f.PacketLength < 0 || f.PacketLength > 255 {
+		return nil, fieldErr("packet_length", "%d is outside 0 to 255", f.PacketLength)
+	}
+	c.PacketLength = byte(f.PacketLength)
+	if f.TxPowerDBm != nil {
+		setting, ok := txPowers[*f.TxPowerDBm]
+		if !ok {
+			return nil, fieldErr("tx_power_dbm", "%d dBm is not one of 10, 7, 5, 0, -10, -30", *f.TxPowerDBm)
+		}
+		c.TxPower = setting
+	}
+
+	if err := c.Validate(); err != nil {
+		var cfgErr *cc1101.ConfigError
+		if errors.As(err, &cfgErr) {
+			return nil, fieldErr(configFields[cfgErr.Field], "%s", cfgErr.Reason)
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// Marshal encodes c in the given format.
+func Marshal(c *cc1101.RadioConfig, format Format) ([]byte, error) {
+	f, err := FromConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	if format == YAML {
+		return yaml.Marshal(f)
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// Unmarshal decodes and validates a configuration file. Unknown fields are
+// rejected so that typos do not silently fall back to defaults.
+func Unmarshal(data []byte, format Format) (*cc1101.RadioConfig, error) {
+	var f File
+	if format == YAML {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&f); err != nil {
+			return nil, fmt.Errorf("invalid YAML configuration: %w", err)
+		}
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&f); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) {
+				return nil, fieldErr(typeErr.Field, "expected %s, got %s", typeErr.Type, typeErr.Value)
+			}
+			return nil, fmt.Errorf("invalid JSON configuration: %w", err)
+		}
+	}
+	return f.Config()
+}
+
+func round(v float64, decimals int) float64 {
+	p := math.Pow(10, float64(decimals))
+	return math.Round(v*p) / p
+}
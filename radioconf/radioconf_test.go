@@ -0,0 +1,163 @@
+package radioconf
+
+import (
+	"cc1101"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// A configuration read back from the registers must program the same
+// registers after a trip through either file format.
+func TestRoundTripPresets(t *testing.T) {
+	for _, p := range cc1101.Presets {
+		want, err := p.Config.Build()
+		if err != nil {
+			t.Fatalf("%s: %v", p.Name, err)
+		}
+		configs := map[string]*cc1101.RadioConfig{
+			"preset":   &p.Config,
+			"readback": cc1101.ConfigFromSnapshot(want),
+		}
+		for source, c := range configs {
+			for _, format := range []Format{JSON, YAML} {
+				data, err := Marshal(c, format)
+				if err != nil {
+					t.Fatalf("%s %s: Marshal: %v", p.Name, source, err)
+				}
+				back, err := Unmarshal(data, format)
+				if err != nil {
+					t.Fatalf("%s %s: Unmarshal: %v\n%s", p.Name, source, err, data)
+				}
+				got, err := back.Build()
+				if err != nil {
+					t.Fatalf("%s %s: Build: %v", p.Name, source, err)
+				}
+				if diff := cc1101.Diff(want, got); len(diff) != 0 {
+					t.Errorf("%s %s, format %d: registers changed: %v\n%s", p.Name, source, format, diff, data)
+				}
+			}
+		}
+	}
+}
+
+// Every channel filter, written with the file's rounding, must select the
+// same filter again.
+func TestRoundTripBandwidths(t *testing.T) {
+	for e := byte(0); e < 4; e++ {
+		for m := byte(0); m < 4; m++ {
+			c := cc1101.PresetGFSK868.Config
+			c.RxBandwidthHz = cc1101.RxBandwidth(e, m)
+			data, err := Marshal(&c, YAML)
+			if err != nil {
+				t.Fatal(err)
+			}
+			back, err := Unmarshal(data, YAML)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s, err := back.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := s.Registers[cc1101.MDMCFG4]>>4, e<<2|m; got != want {
+				t.Errorf("%.4f kHz: CHANBW_E/M = %d/%d, want %d/%d", c.RxBandwidthHz/1e3, got>>2, got&3, e, m)
+			}
+		}
+	}
+}
+
+func validFile() File {
+	dbm := 10
+	return File{
+		Version:        Version,
+		FrequencyMHz:   868.3,
+		Modulation:     "GFSK",
+		DataRateKBaud:  38.4,
+		DeviationKHz:   20,
+		RxBandwidthKHz: 100,
+		SyncWord:       "0xD391",
+		SyncMode:       "16/16",
+		PreambleBytes:  4,
+		LengthMode:     "variable",
+		PacketLength:   255,
+		CRC:            true,
+		TxPowerDBm:     &dbm,
+	}
+}
+
+func TestFieldErrors(t *testing.T) {
+	tests := []struct {
+		field string
+		edit  func(f *File)
+	}{
+		{"version", func(f *File) { f.Version = 0 }},
+		{"version", func(f *File) { f.Version = Version + 1 }},
+		{"frequency_mhz", func(f *File) { f.FrequencyMHz = 500 }},
+		{"modulation", func(f *File) { f.Modulation = "FM" }},
+		{"data_rate_kbaud", func(f *File) { f.DataRateKBaud = 1000 }},
+		{"deviation_khz", func(f *File) { f.DeviationKHz = 500 }},
+		{"rx_bandwidth_khz", func(f *File) { f.RxBandwidthKHz = 900 }},
+		{"sync_word", func(f *File) { f.SyncWord = "0x12345" }},
+		{"sync_mode", func(f *File) { f.SyncMode = "8/8" }},
+		{"packet_format", func(f *File) { f.PacketFormat = "raw" }},
+		{"length_mode", func(f *File) { f.LengthMode = "short" }},
+		{"packet_length", func(f *File) { f.PacketLength = 256 }},
+		{"tx_power_dbm", func(f *File) { dbm := 3; f.TxPowerDBm = &dbm }},
+	}
+	for _, tt := range tests {
+		f := validFile()
+		tt.edit(&f)
+		data, err := json.Marshal(&f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = Unmarshal(data, JSON)
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) || fieldErr.Field != tt.field {
+			t.Errorf("%s: Unmarshal error = %v, want a FieldError for %s", data, err, tt.field)
+		}
+	}
+
+	f := validFile()
+	data, _ := json.Marshal(&f)
+	if _, err := Unmarshal(data, JSON); err != nil {
+		t.Errorf("valid file rejected: %v", err)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	var fieldErr *FieldError
+	_, err := Unmarshal([]byte(`{"version": 1, "crc": "yes"}`), JSON)
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "crc" {
+		t.Errorf("JSON type error = %v, want a FieldError for crc", err)
+	}
+	if _, err := Unmarshal([]byte(`{"version": 1, "frequency": 868}`), JSON); err == nil {
+		t.Error("unknown JSON field accepted")
+	}
+	if _, err := Unmarshal([]byte("version: 1\nfrequency: 868\n"), YAML); err == nil {
+		t.Error("unknown YAML field accepted")
+	}
+}
+
+func TestFromConfigTxPower(t *testing.T) {
+	c := cc1101.PresetGFSK868.Config
+	c.TxPower = 0x42
+	_, err := FromConfig(&c)
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "tx_power_dbm" {
+		t.Errorf("FromConfig error = %v, want a FieldError for tx_power_dbm", err)
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := map[string]Format{"radio.json": JSON, "radio.YAML": YAML, "a/b.yml": YAML}
+	for path, want := range tests {
+		if got, err := FormatFromPath(path); err != nil || got != want {
+			t.Errorf("FormatFromPath(%q) = %d, %v; want %d", path, got, err, want)
+		}
+	}
+	if _, err := FormatFromPath("radio.toml"); err == nil {
+		t.Error("FormatFromPath accepted .toml")
+	}
+}
@@ -0,0 +1,282 @@
+package cc1101
+
+import "fmt"
+
+// RadioConfig describes a radio configuration in physical units. Build
+// checks that the options can be used together and computes every register,
+// including the AGC, FOC, bit synchronization, front end and test settings
+// SmartRF Studio recommends for the data rate.
+type RadioConfig struct {
+	FrequencyHz   float64
+	Modulation    Modulation
+	DataRate      float64 // Baud
+	DeviationHz   float64 // Ignored for OOK and MSK
+	RxBandwidthHz float64 // 0 picks the narrowest filter fitting the signal
+	SyncWord      uint16
+	SyncMode      SyncMode
+	PreambleBytes int // Rounded up to the next NUM_PREAMBLE step, 0 means 4
+	Format        PacketFormat
+	Length        LengthConfig
+	PacketLength  byte // Fixed length, or maximum length in variable mode
+	CRC           bool
+	Whitening     bool
+	Manchester    bool
+	FEC           bool
+	TxPower       byte // PATABLE setting, 0 means Power_0dBm
+}
+
+// ConfigError reports the RadioConfig field that cannot be used.
+type ConfigError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Reason)
+}
+
+// Supported symbol rate range of each modulation, in baud.
+// Read page 8 https://www.ti.com/lit/ds/symlink/cc1101.pdf
+var dataRateLimits = map[Modulation][2]float64{
+	Modulation2FSK: {600, 500000},
+	ModulationGFSK: {600, 250000},
+	ModulationOOK:  {600, 250000},
+	Modulation4FSK: {600, 300000},
+	ModulationMSK:  {26000, 500000},
+}
+
+// Validate checks the configuration without computing the registers.
+func (c *RadioConfig) Validate() error {
+	_, err := c.Build()
+	return err
+}
+
+// Build computes the complete register set and PATABLE for c.
+func (c *RadioConfig) Build() (*Snapshot, error) {
+	f := c.FrequencyHz
+	if !(f >= 300e6 && f <= 348e6) && !(f >= 387e6 && f <= 464e6) && !(f >= 779e6 && f <= 928e6) {
+		return nil, &ConfigError{"FrequencyHz", fmt.Sprintf("%.0f Hz is outside the 300-348, 387-464 and 779-928 MHz bands", f)}
+	}
+	limits, ok := dataRateLimits[c.Modulation]
+	if !ok {
+		return nil, &ConfigError{"Modulation", fmt.Sprintf("unknown modulation %s", c.Modulation)}
+	}
+	if c.DataRate < limits[0] || c.DataRate > limits[1] {
+		return nil, &ConfigError{"DataRate", fmt.Sprintf("%s supports %.0f to %.0f baud, got %.0f", c.Modulation, limits[0], limits[1], c.DataRate)}
+	}
+	if c.Manchester && (c.Modulation == Modulation4FSK || c.Modulation == ModulationMSK) {
+		return nil, &ConfigError{"Manchester", fmt.Sprintf("not supported with %s", c.Modulation)}
+	}
+	if c.Manchester && c.FEC {
+		return nil, &ConfigError{"Manchester", "not supported together with FEC"}
+	}
+	if c.FEC && c.Length != LengthFixed {
+		return nil, &ConfigError{"FEC", "requires fixed packet length"}
+	}
+	if c.FEC && c.Format != FormatNormal {
+		return nil, &ConfigError{"FEC", "requires the FIFO packet format"}
+	}
+	if c.SyncMode > Sync30of32CS {
+		return nil, &ConfigError{"SyncMode", fmt.Sprintf("unknown sync mode %d", c.SyncMode)}
+	}
+	if c.Format > FormatAsyncSerial {
+		return nil, &ConfigError{"Format", fmt.Sprintf("unknown packet format %d", c.Format)}
+	}
+	if c.Length > LengthInfinite {
+		return nil, &ConfigError{"Length", fmt.Sprintf("unknown length mode %d", c.Length)}
+	}
+	if c.Length == LengthFixed && c.Format == FormatNormal && c.PacketLength == 0 {
+		return nil, &ConfigError{"PacketLength", "must be at least 1 in fixed length mode"}
+	}
+
+	preamble := c.PreambleBytes
+	if preamble == 0 {
+		preamble = 4
+	}
+	numPreamble, ok := preambleSetting(preamble)
+	if preamble < 0 || !ok {
+		return nil, &ConfigError{"PreambleBytes", fmt.Sprintf("%d is outside 1 to 24 bytes", c.PreambleBytes)}
+	}
+
+	drateE, drateM, _ := dataRateSetting(c.DataRate)
+
+	deviation := c.DeviationHz
+	switch c.Modulation {
+	case ModulationOOK, ModulationMSK:
+		deviation = 0
+	default:
+		if deviation < Deviation(0, 0) || deviation > Deviation(7, 7) {
+			return nil, &ConfigError{"DeviationHz", fmt.Sprintf("%.0f Hz is outside %.0f to %.0f Hz", deviation, Deviation(0, 0), Deviation(7, 7))}
+		}
+	}
+
+	bandwidth := c.RxBandwidthHz
+	if bandwidth == 0 {
+		// Carson's rule, with the signal filling 80% of the filter.
+		signal := c.DataRate + 2*deviation
+		switch c.Modulation {
+		case ModulationOOK:
+			signal = 2 * c.DataRate
+		case ModulationMSK:
+			signal = 1.5 * c.DataRate
+		}
+		bandwidth = signal / 0.8
+		if max := RxBandwidth(0, 0); bandwidth > max {
+			bandwidth = max
+		}
+	}
+	chanbwE, chanbwM, ok := rxBandwidthSetting(bandwidth)
+	if !ok {
+		return nil, &ConfigError{"RxBandwidthHz", fmt.Sprintf("%.0f Hz is wider than %.0f Hz", bandwidth, RxBandwidth(0, 0))}
+	}
+	bandwidth = RxBandwidth(chanbwE, chanbwM)
+
+	s := DefaultSnapshot()
+	regs := &s.Registers
+	rate := c.DataRate
+	ook := c.Modulation == ModulationOOK
+
+	set := func(r Register) { regs[r.Addr()] = r.Encode() }
+
+	gdo0 := Iocfg0{Config: 0x06} // Sync word sent/received
+	switch c.Format {
+	case FormatSyncSerial:
+		gdo0.Config = 0x0C
+	case FormatAsyncSerial:
+		gdo0.Config = 0x0D
+	}
+	set(&Iocfg2{Config: 0x29}) // CHIP_RDYn
+	set(&gdo0)
+	set(&Fifothr{ADCRetention: true, Threshold: 7})
+
+	regs[SYNC1] = byte(c.SyncWord >> 8)
+	regs[SYNC0] = byte(c.SyncWord)
+	regs[PKTLEN] = c.PacketLength
+	if c.Length != LengthFixed && c.PacketLength == 0 {
+		regs[PKTLEN] = 0xFF
+	}
+	set(&Pktctrl1{AppendStatus: true})
+	set(&Pktctrl0{Whitening: c.Whitening, Format: c.Format, CRC: c.CRC, Length: c.Length})
+
+	freq := frequencyWord(c.FrequencyHz)
+	regs[FREQ2] = byte(freq >> 16)
+	regs[FREQ1] = byte(freq >> 8)
+	regs[FREQ0] = byte(freq)
+
+	fsctrl1 := Fsctrl1{FreqIF: 0x06}
+	switch {
+	case rate > 300000:
+		fsctrl1.FreqIF = 0x0E
+	case rate > 150000:
+		fsctrl1.FreqIF = 0x0C
+	case rate > 50000:
+		fsctrl1.FreqIF = 0x08
+	}
+	set(&fsctrl1)
+	regs[FSCTRL0] = 0x00
+
+	set(&Mdmcfg4{ChanBwE: chanbwE, ChanBwM: chanbwM, DrateE: drateE})
+	set(&Mdmcfg3{DrateM: drateM})
+	set(&Mdmcfg2{Modulation: c.Modulation, Manchester: c.Manchester, SyncMode: c.SyncMode})
+	set(&Mdmcfg1{FEC: c.FEC, NumPreamble: numPreamble, ChanSpcE: 2})
+	set(&Mdmcfg0{ChanSpcM: 0xF8})
+	if deviation > 0 {
+		devE, devM := deviationSetting(deviation)
+		set(&Deviatn{DeviationE: devE, DeviationM: devM})
+	} else if c.Modulation == ModulationMSK {
+		set(&Deviatn{})
+	}
+
+	set(&Mcsm2{RxTime: 7})
+	set(&Mcsm1{CCAMode: CCARSSIAndPacket})
+	set(&Mcsm0{AutoCal: AutoCalFromIdle, POTimeout: 2})
+
+	if rate >= 200000 {
+		set(&Foccfg{PreK: 3, PostK: true, Limit: 1})              // 0x1D
+		set(&Bscfg{PreKP: 1, PostKI: true, PostKP: true})         // 0x1C
+		set(&Agcctrl2{MaxDVGAGain: 3, MagnTarget: 7})             // 0xC7
+		set(&Agcctrl0{HystLevel: 2, WaitTime: 3})                 // 0xB0
+		set(&Fscal3{Config: 3, ChargePumpCalEn: 2, Result: 0x0A}) // 0xEA
+	} else {
+		set(&Foccfg{PreK: 2, PostK: true, Limit: 2})                // 0x16
+		set(&Bscfg{PreKI: 1, PreKP: 2, PostKI: true, PostKP: true}) // 0x6C
+		if rate < 10000 || ook {
+			set(&Agcctrl2{MagnTarget: 3}) // 0x03
+		} else {
+			set(&Agcctrl2{MaxDVGAGain: 1, MagnTarget: 3}) // 0x43
+		}
+		set(&Agcctrl0{HystLevel: 2, WaitTime: 1, FilterLength: 1}) // 0x91
+		set(&Fscal3{Config: 3, ChargePumpCalEn: 2, Result: 0x09})  // 0xE9
+	}
+	if ook {
+		set(&Agcctrl1{}) // LNA2 gain reduced first, as DN022 recommends for OOK
+	} else {
+		set(&Agcctrl1{LNAPriority: true})
+	}
+	set(&Worctrl{RCPowerDown: true, Event1: 7, RCCal: true, WORRes: 3})
+	if rate >= 100000 {
+		set(&Frend1{LNACurrent: 2, LNA2MixCurrent: 3, LODivBufCurrentRX: 1, MixCurrent: 2}) // 0xB6
+	} else {
+		set(&Frend1{LNACurrent: 1, LNA2MixCurrent: 1, LODivBufCurrentRX: 1, MixCurrent: 2}) // 0x56
+	}
+	set(&Fscal2{VCOCoreHigh: true, Result: 0x0A})
+	set(&Fscal1{})
+	set(&Fscal0{Value: 0x1F})
+	if bandwidth <= 325000 {
+		regs[TEST2], regs[TEST1] = 0x81, 0x35 // Improved sensitivity below 325 kHz
+	}
+	regs[TEST0] = 0x09
+
+	power := c.TxPower
+	if power == 0 {
+		power = Power_0dBm
+	}
+	if ook {
+		// PATABLE[0] for a '0' symbol, PATABLE[1] for a '1'.
+		set(&Frend0{LODivBufCurrentTX: 1, PAPower: 1})
+		s.PATable = [PATABLE_SIZE]byte{0x00, power}
+	} else {
+		set(&Frend0{LODivBufCurrentTX: 1})
+		s.PATable = [PATABLE_SIZE]byte{power}
+	}
+	return s, nil
+}
+
+// ApplyConfig computes the registers for c and writes them to the chip.
+func (d *Device) ApplyConfig(c *RadioConfig) error {
+	s, err := c.Build()
+	if err != nil {
+		return err
+	}
+	return d.Restore(s)
+}
+
+// ConfigFromSnapshot recovers the physical parameters programmed in s. The
+// values are those the chip actually uses, so they differ from the ones
+// given to Build by the register quantisation.
+func ConfigFromSnapshot(s *Snapshot) *RadioConfig {
+	r := DecodeSnapshot(s)
+	c := &RadioConfig{
+		FrequencyHz:   r.CarrierHz,
+		Modulation:    r.Mdmcfg2.Modulation,
+		DataRate:      r.DataRate,
+		RxBandwidthHz: r.RxBandwidthHz,
+		SyncWord:      r.SyncWord,
+		SyncMode:      r.Mdmcfg2.SyncMode,
+		PreambleBytes: r.PreambleBytes,
+		Format:        r.Pktctrl0.Format,
+		Length:        r.Pktctrl0.Length,
+		PacketLength:  r.PacketLength,
+		CRC:           r.Pktctrl0.CRC,
+		Whitening:     r.Pktctrl0.Whitening,
+		Manchester:    r.Mdmcfg2.Manchester,
+		FEC:           r.Mdmcfg1.FEC,
+		TxPower:       s.PATable[r.Frend0.PAPower],
+	}
+	switch c.Modulation {
+	case ModulationOOK, ModulationMSK:
+	default:
+		c.DeviationHz = r.DeviationHz
+	}
+	return c
+}
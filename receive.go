@@ -0,0 +1,147 @@
+package cc1101
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoPacket is returned by ReceiveData when the RX FIFO is empty.
+var ErrNoPacket = errors.New("no packet received")
+
+// Time allowed for the rest of a packet to arrive once its length byte is in
+// the RX FIFO.
+const rxPacketTimeout = 100 * time.Millisecond
+
+// Packet is a packet read from the RX FIFO.
+type Packet struct {
+	// Address is the destination address byte, set when address checking
+	// is enabled (Addressed is then true).
+	Address   byte
+	Addressed bool
+	Data      []byte
+
+	// Appended status bytes, valid when PKTCTRL1 APPEND_STATUS is set.
+	RSSI  byte // raw RSSI, see RSSIdBm
+	LQI   byte // link quality indicator, lower is better
+	CRCOK bool
+}
+
+// RSSIdBm converts a raw RSSI value to dBm.
+func RSSIdBm(raw byte) float64 {
+	return float64(int8(raw))/2 - RSSI_OFFSET_868MHZ
+}
+
+// ReceiveData reads one packet from the RX FIFO in the configured length
+// mode. The radio must have been put in RX with SetRx. It returns
+// ErrNoPacket if nothing has been received yet. In infinite length mode
+// there are no packet boundaries and Data holds the bytes currently in the
+// FIFO.
+func (d *Device) ReceiveData() (*Packet, error) {
+	p := new(Packet)
+	if err := d.ReceiveDataInto(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ReceiveDataInto is ReceiveData storing the packet in p and reusing the
+// capacity of p.Data, so that a receive loop does not allocate.
+func (d *Device) ReceiveDataInto(p *Packet) error {
+	d.rxMu.Lock()
+	defer d.rxMu.Unlock()
+	*p = Packet{Data: p.Data[:0]}
+
+	rxBytes, overflow, err := d.RxBytes()
+	if err != nil {
+		return fmt.Errorf("failed to read RXBYTES: %w", err)
+	}
+	if overflow {
+		return d.recoverRxOverflow()
+	}
+	if rxBytes == 0 {
+		return ErrNoPacket
+	}
+
+	pktctrl1, pktctrl0 := d.packetConfig()
+	if d.fecEnabled() && pktctrl0.Length != LengthFixed {
+		return fmt.Errorf("FEC requires fixed packet length, configured %s", pktctrl0.Length)
+	}
+	if pktctrl0.Length == LengthInfinite {
+		if err := d.ReadBurstRegisterInto(RXFIFO_SINGLE_BYTE, d.rxFIFO[:rxBytes]); err != nil {
+			return fmt.Errorf("failed to read RX FIFO: %w", err)
+		}
+		p.Data = append(p.Data, d.rxFIFO[:rxBytes]...)
+		return nil
+	}
+
+	length := d.shadow(PKTLEN)
+	if pktctrl0.Length == LengthVariable {
+		length, err = d.ReadSingleRegister(RXFIFO_SINGLE_BYTE)
+		if err != nil {
+			return fmt.Errorf("failed to read RX FIFO: %w", err)
+		}
+	}
+	addressed := pktctrl1.AddressCheck != AddrCheckNone
+	status := 0
+	if pktctrl1.AppendStatus {
+		status = 2
+	}
+	if addressed && length == 0 {
+		d.SpiStrobe(SIDLE)
+		d.SpiStrobe(SFRX)
+		return errors.New("received empty packet without address byte")
+	}
+
+	want := int(length) + status
+	if want > FIFOBUFFER {
+		d.SpiStrobe(SIDLE)
+		d.SpiStrobe(SFRX)
+		return fmt.Errorf("packet too long: %d bytes do not fit in the RX FIFO", want)
+	}
+
+	// Wait for the rest of the packet. The status byte counts up to 15
+	// bytes in the RX FIFO, RXBYTES is only read for longer packets.
+	deadline := time.Now().Add(rxPacketTimeout)
+	for {
+		st, err := d.Status()
+		if err != nil {
+			return fmt.Errorf("failed to read status: %w", err)
+		}
+		if st.State() == ChipStateRxOverflow {
+			return d.recoverRxOverflow()
+		}
+		available := st.FIFOBytes()
+		if available == 15 && want > 15 {
+			available, _, err = d.RxBytes()
+			if err != nil {
+				return fmt.Errorf("failed to read RXBYTES: %w", err)
+			}
+		}
+		if available >= want {
+			break
+		}
+		if time.Now().After(deadline) {
+			d.SpiStrobe(SIDLE)
+			d.SpiStrobe(SFRX)
+			return fmt.Errorf("incomplete packet: %d of %d bytes received", available, want)
+		}
+		time.Sleep(1 * time.Millisecond)
+	}
+
+	buf := d.rxFIFO[:want]
+	if err := d.ReadBurstRegisterInto(RXFIFO_SINGLE_BYTE, buf); err != nil {
+		return fmt.Errorf("failed to read RX FIFO: %w", err)
+	}
+	data := buf[:length]
+	if addressed {
+		p.Address, p.Addressed, data = data[0], true, data[1:]
+	}
+	p.Data = append(p.Data, data...)
+	if status > 0 {
+		p.RSSI = buf[length]
+		p.LQI = buf[length+1] & 0x7F
+		p.CRCOK = buf[length+1]&0x80 != 0
+	}
+	return nil
+}
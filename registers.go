@@ -5,7 +5,8 @@ const (
 	CRYSTAL_FREQUENCY       = 26000000
 	CFG_REGISTER            = 0x2F // 47 registers
 	FIFOBUFFER              = 0x40 // size of Fifo Buffer
-	RSSI_OFFSET_868MHZ      = 0x4E // dec = 74
+	PATABLE_SIZE            = 0x08 // size of PA power table
+	RSSI_OFFSET_868MHZ      = 0x4A // dec = 74
 	TX_RETRIES_MAX          = 0x05 // tx_retries_max
 	ACK_TIMEOUT             = 200  // ACK timeout in ms
 	CC1100_COMPARE_REGISTER = 0x00 // register compare 0=no compare 1=compare
@@ -0,0 +1,105 @@
+package cc1101
+
+// Register names, and the field layout of each register as derived from its
+// typed model in bitfields.go, used to name the bits that differ between two
+// snapshots and to annotate register dumps.
+
+var registerNames = [CFG_REGISTER]string{
+	"IOCFG2", "IOCFG1", "IOCFG0", "FIFOTHR", "SYNC1", "SYNC0", "PKTLEN", "PKTCTRL1",
+	"PKTCTRL0", "ADDR", "CHANNR", "FSCTRL1", "FSCTRL0", "FREQ2", "FREQ1", "FREQ0",
+	"MDMCFG4", "MDMCFG3", "MDMCFG2", "MDMCFG1", "MDMCFG0", "DEVIATN", "MCSM2", "MCSM1",
+	"MCSM0", "FOCCFG", "BSCFG", "AGCCTRL2", "AGCCTRL1", "AGCCTRL0", "WOREVT1", "WOREVT0",
+	"WORCTRL", "FREND1", "FREND0", "FSCAL3", "FSCAL2", "FSCAL1", "FSCAL0", "RCCTRL1",
+	"RCCTRL0", "FSTEST", "PTEST", "AGCTEST", "TEST2", "TEST1", "TEST0",
+}
+
+// field is a register field as decoded by a typed register, for the
+// registers implementing fielder.
+type field struct {
+	name  string
+	value int
+}
+
+type fielder interface {
+	fields() []field
+}
+
+func boolValue(v bool) int {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// fieldInfo is a register field and the bits it occupies.
+type fieldInfo struct {
+	name string
+	mask byte
+}
+
+// value extracts the field from a register value, shifted down to bit 0.
+func (f fieldInfo) value(reg byte) byte {
+	v, m := reg&f.mask, f.mask
+	for m&1 == 0 {
+		v, m = v>>1, m>>1
+	}
+	return v
+}
+
+// registerLayout returns the fields of the register at addr, finding the
+// bits of each by decoding one bit at a time through its typed model. Bits
+// in no field are reserved. A register without a typed model is a single
+// 8-bit field named after it.
+func registerLayout(addr byte) []fieldInfo {
+	r := newRegister(addr)
+	if r == nil {
+		return []fieldInfo{{registerNames[addr], 0xFF}}
+	}
+	f := r.(fielder)
+	// Decode errors only report reserved bits or invalid values; the
+	// fields are decoded regardless.
+	r.Decode(0)
+	zero := f.fields()
+	layout := make([]fieldInfo, len(zero))
+	for i := range zero {
+		layout[i].name = zero[i].name
+	}
+	for b := 7; b >= 0; b-- {
+		r.Decode(1 << b)
+		for i, fv := range f.fields() {
+			if fv.value != zero[i].value {
+				layout[i].mask |= 1 << b
+			}
+		}
+	}
+	return layout
+}
+
+// reservedMask returns the bits of a register that are in none of its
+// fields.
+func reservedMask(layout []fieldInfo) byte {
+	mask := byte(0xFF)
+	for _, f := range layout {
+		mask &^= f.mask
+	}
+	return mask
+}
+
+// RegisterName returns the datasheet name of a configuration register, or
+// the empty string for addresses outside 0x00-0x2E.
+func RegisterName(addr byte) string {
+	if addr >= CFG_REGISTER {
+		return ""
+	}
+	return registerNames[addr]
+}
+
+// registerAddr looks up a configuration register by its datasheet name.
+func registerAddr(name string) (byte, bool) {
+	for addr, n := range registerNames {
+		if n == name {
+			return byte(addr), true
+		}
+	}
+	return 0, false
+}
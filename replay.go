@@ -0,0 +1,144 @@
+package cc1101
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// ReplaySPI is an SPI bus playing back a recorded trace, for regression
+// tests without hardware. It answers each Tx with the recorded MISO bytes
+// and fails as soon as the driver sends a byte differing from the recorded
+// MOSI bytes.
+//
+//	bus := cc1101.NewReplaySPI(trace)
+//	d := cc1101.New(bus, bus.CS, bus.MISO)
+//	err := d.SendData(packet)
+//	if err := bus.Done(); err != nil { ... }
+type ReplaySPI struct {
+	mu    sync.Mutex
+	trace []Transaction
+	next  int // transaction being replayed
+	off   int // bytes of it already exchanged
+	err   error
+}
+
+// NewReplaySPI returns a bus replaying trace, as recorded by a Recorder or
+// read by ReadTrace.
+func NewReplaySPI(trace []Transaction) *ReplaySPI {
+	return &ReplaySPI{trace: trace}
+}
+
+func (r *ReplaySPI) Tx(w, rd []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	if r.next >= len(r.trace) {
+		r.err = fmt.Errorf("replay: unexpected transaction % X after the end of the trace", w)
+		return r.err
+	}
+	t := &r.trace[r.next]
+	end := r.off + len(w)
+	if end > len(t.MOSI) || string(w) != string(t.MOSI[r.off:end]) {
+		r.err = fmt.Errorf("replay: transaction %d: sent % X, want % X (%s %s)",
+			r.next, w, t.MOSI[r.off:min(end, len(t.MOSI))], t.Op(), t.Name())
+		return r.err
+	}
+	copy(rd, t.MISO[r.off:end])
+	r.off = end
+	if r.off == len(t.MOSI) {
+		return t.Err
+	}
+	return nil
+}
+
+// CS is the chip select pin of the bus; releasing it ends the transaction.
+func (r *ReplaySPI) CS(state bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !state || r.off == 0 || r.err != nil {
+		return
+	}
+	if t := &r.trace[r.next]; r.off < len(t.MOSI) {
+		r.err = fmt.Errorf("replay: transaction %d: ended after %d of %d bytes (%s %s)",
+			r.next, r.off, len(t.MOSI), t.Op(), t.Name())
+		return
+	}
+	r.next++
+	r.off = 0
+}
+
+// MISO is the MISO pin of the bus: the chip is always ready.
+func (r *ReplaySPI) MISO() bool {
+	return false
+}
+
+// Done returns the first mismatch between the driver and the trace, or an
+// error if part of the trace was not replayed.
+func (r *ReplaySPI) Done() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	if r.next < len(r.trace) {
+		t := &r.trace[r.next]
+		return fmt.Errorf("replay: %d of %d transactions not replayed, next is %s %s",
+			len(r.trace)-r.next, len(r.trace), t.Op(), t.Name())
+	}
+	return nil
+}
+
+// WriteTrace writes transactions in the text format read by ReadTrace: one
+// transaction per line, the MOSI bytes then the MISO bytes in hex, followed
+// by a comment describing it.
+//
+//	36 | 1F  # strobe SIDLE
+func WriteTrace(w io.Writer, trace []Transaction) error {
+	for i := range trace {
+		t := &trace[i]
+		if _, err := fmt.Fprintf(w, "% X | % X  # %s %s\n", t.MOSI, t.MISO, t.Op(), t.Name()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadTrace reads transactions written by WriteTrace. Blank lines and text
+// after '#' are ignored.
+func ReadTrace(rd io.Reader) ([]Transaction, error) {
+	var trace []Transaction
+	s := bufio.NewScanner(rd)
+	for line := 1; s.Scan(); line++ {
+		text, _, _ := strings.Cut(s.Text(), "#")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		mosiText, misoText, ok := strings.Cut(text, "|")
+		if !ok {
+			return nil, fmt.Errorf("trace line %d: missing '|' between MOSI and MISO bytes", line)
+		}
+		mosi, err := parseHexBytes(mosiText)
+		if err != nil {
+			return nil, fmt.Errorf("trace line %d: MOSI: %w", line, err)
+		}
+		miso, err := parseHexBytes(misoText)
+		if err != nil {
+			return nil, fmt.Errorf("trace line %d: MISO: %w", line, err)
+		}
+		if len(mosi) == 0 || len(mosi) != len(miso) {
+			return nil, fmt.Errorf("trace line %d: %d MOSI bytes and %d MISO bytes", line, len(mosi), len(miso))
+		}
+		trace = append(trace, Transaction{MOSI: mosi, MISO: miso})
+	}
+	return trace, s.Err()
+}
+
+func parseHexBytes(s string) ([]byte, error) {
+	return hex.DecodeString(strings.Join(strings.Fields(s), ""))
+}
@@ -0,0 +1,111 @@
+package cc1101
+
+import (
+	"context"
+	"math/bits"
+	"time"
+)
+
+// Configuration scrubbing. In electrically noisy environments a register
+// can be corrupted without the chip resetting. Scrub reads back the whole
+// configuration and rewrites each register that differs from the shadow
+// copy, counting the bits it had to correct.
+
+// ScrubStats counts the scrubs made and the bits they corrected since New.
+type ScrubStats struct {
+	Scrubs uint32
+
+	// Bits corrected in each configuration register, indexed by address,
+	// and in the PATABLE as a whole.
+	Registers [CFG_REGISTER]uint32
+	PATable   uint32
+}
+
+// Total is the number of bits corrected in all registers and the PATABLE.
+func (s ScrubStats) Total() uint32 {
+	total := s.PATable
+	for _, n := range s.Registers {
+		total += n
+	}
+	return total
+}
+
+// ScrubStats returns the scrub counters.
+func (d *Device) ScrubStats() ScrubStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.scrubStats
+}
+
+// Scrub reads back the configuration registers and PATABLE, rewrites those
+// that differ from the values written by the driver and returns the number
+// of bits corrected. The calibration results in FSCAL3-FSCAL1 are left as
+// the chip set them. Registers are rewritten in place, without leaving RX
+// or TX.
+//
+// Like CheckConfig, Scrub returns ErrConfigUnknown until Reset, ApplyConfig,
+// ApplyPreset, Restore or LoadShadow has run.
+func (d *Device) Scrub() (int, error) {
+	d.cfgMu.Lock()
+	defer d.cfgMu.Unlock()
+
+	want, err := d.knownShadow()
+	if err != nil {
+		return 0, err
+	}
+	var got Snapshot
+	if err := d.readSnapshot(&got); err != nil {
+		return 0, err
+	}
+
+	var corrected [CFG_REGISTER]int
+	patable, total := 0, 0
+	for addr := range got.Registers {
+		diff := (got.Registers[addr] ^ want.Registers[addr]) &^ volatileBits[addr]
+		if diff == 0 {
+			continue
+		}
+		value := want.Registers[addr]&^volatileBits[addr] | got.Registers[addr]&volatileBits[addr]
+		if err = d.WriteSingleRegister(byte(addr), value); err != nil {
+			break
+		}
+		corrected[addr] = bits.OnesCount8(diff)
+		total += corrected[addr]
+	}
+	if err == nil && got.PATable != want.PATable {
+		if err = d.WriteBurstRegister(PATABLE, want.PATable[:]); err == nil {
+			for i := range got.PATable {
+				patable += bits.OnesCount8(got.PATable[i] ^ want.PATable[i])
+			}
+			total += patable
+		}
+	}
+
+	d.mu.Lock()
+	d.scrubStats.Scrubs++
+	for addr, n := range corrected {
+		d.scrubStats.Registers[addr] += uint32(n)
+	}
+	d.scrubStats.PATable += uint32(patable)
+	d.mu.Unlock()
+	return total, err
+}
+
+// Scrubber calls Scrub every interval until ctx is done. Errors are passed
+// to onError, which may be nil; the scrubber keeps running after them.
+//
+//	go cc.Scrubber(ctx, time.Minute, nil)
+func (d *Device) Scrubber(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if _, err := d.Scrub(); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
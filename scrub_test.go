@@ -0,0 +1,84 @@
+package cc1101
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestScrub(t *testing.T) {
+	d, chip := newFakeDevice()
+	if err := d.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetSyncWord(0xD391); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := d.Scrub(); n != 0 || err != nil {
+		t.Fatalf("Scrub() on a clean chip = %d, %v; want 0, nil", n, err)
+	}
+
+	// Flip three bits in SYNC1, one in PKTLEN and two in the PATABLE, and
+	// upset the calibration, which Scrub must leave alone.
+	chip.mu.Lock()
+	want := chip.regs
+	chip.regs[SYNC1] ^= 0x07
+	chip.regs[PKTLEN] ^= 0x10
+	chip.regs[FSCAL1] ^= 0x01
+	chip.patable[3] ^= 0x81
+	chip.mu.Unlock()
+	want[FSCAL1] ^= 0x01
+
+	n, err := d.Scrub()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 6 {
+		t.Errorf("Scrub() corrected %d bits, want 6", n)
+	}
+	if chip.regs != want {
+		t.Errorf("registers after Scrub:\n% X\nwant\n% X", chip.regs, want)
+	}
+	if chip.patable != resetPATable {
+		t.Errorf("PATABLE after Scrub = % X, want % X", chip.patable, resetPATable)
+	}
+
+	if total := d.ScrubStats().Total(); total != 6 {
+		t.Errorf("ScrubStats().Total() = %d, want 6", total)
+	}
+	stats := d.ScrubStats()
+	if stats.Scrubs != 2 || stats.Registers[SYNC1] != 3 || stats.Registers[PKTLEN] != 1 || stats.PATable != 2 {
+		t.Errorf("ScrubStats() = %+v", stats)
+	}
+}
+
+// A chip configured before New, e.g. by an earlier run, must not be put
+// back to the reset defaults New assumes.
+func TestScrubConfigUnknown(t *testing.T) {
+	d, chip := newFakeDevice()
+	chip.regs[SYNC1] = 0x12
+	chip.patable[0] = Power_0dBm
+	configured := chip.regs
+
+	if _, err := d.Scrub(); !errors.Is(err, ErrConfigUnknown) {
+		t.Errorf("Scrub before LoadShadow = %v, want ErrConfigUnknown", err)
+	}
+	if _, err := d.CheckConfig(); !errors.Is(err, ErrConfigUnknown) {
+		t.Errorf("CheckConfig before LoadShadow = %v, want ErrConfigUnknown", err)
+	}
+	if chip.regs != configured {
+		t.Fatal("registers written before the configuration is known")
+	}
+
+	if err := d.LoadShadow(); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := d.Scrub(); n != 0 || err != nil {
+		t.Errorf("Scrub after LoadShadow = %d, %v; want 0, nil", n, err)
+	}
+	if event, err := d.CheckConfig(); event != nil || err != nil {
+		t.Errorf("CheckConfig after LoadShadow = %v, %v; want no event", event, err)
+	}
+	if chip.regs != configured || chip.patable[0] != Power_0dBm {
+		t.Error("configuration changed after LoadShadow")
+	}
+}
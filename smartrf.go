@@ -0,0 +1,213 @@
+package cc1101
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Settings is a partial configuration as exported by SmartRF Studio: only the
+// registers the export listed, in address order.
+type Settings struct {
+	Registers []RegisterValue
+	PATable   []byte // nil if the export had no PA table
+}
+
+type RegisterValue struct {
+	Addr  byte
+	Value byte
+}
+
+var (
+	smartRFComments = regexp.MustCompile(`(?s)/\*.*?\*/|//[^\n]*|<!--.*?-->`)
+	smartRFTokens   = regexp.MustCompile(`0[xX][0-9A-Fa-f]+|[A-Za-z_][A-Za-z0-9_]*`)
+	smartRFPATable  = regexp.MustCompile(`^PA_?TABLE([0-7])$`)
+)
+
+// ParseSmartRF reads a SmartRF Studio register export. The C header
+// (#define SMARTRF_SETTING_IOCFG2 0x29), the register settings array
+// ({CC1101_IOCFG2, 0x29}), halRfWriteReg calls, the XML export and plain
+// "name [address] value" tables are recognised. Status registers and
+// unknown names are ignored.
+func ParseSmartRF(r io.Reader) (*Settings, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	text := smartRFComments.ReplaceAllString(string(data), " ")
+
+	var s smartRFBuilder
+	if strings.HasPrefix(strings.TrimSpace(text), "<") {
+		err = s.parseXML(text)
+	} else {
+		err = s.parseText(text)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(s.regs) == 0 && s.patable == nil {
+		return nil, fmt.Errorf("no CC1101 register settings found")
+	}
+	return s.settings(), nil
+}
+
+type smartRFBuilder struct {
+	regs    map[byte]byte
+	patable []byte
+}
+
+// set records value for the register or PA table entry called name. Other
+// names are ignored.
+func (s *smartRFBuilder) set(name string, value byte) {
+	name = strings.ToUpper(name)
+	for _, prefix := range []string{"SMARTRF_SETTING_", "CC1101_", "CCXXX0_", "CC110L_"} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	if m := smartRFPATable.FindStringSubmatch(name); m != nil {
+		i := int(m[1][0] - '0')
+		if len(s.patable) <= i {
+			s.patable = append(s.patable, make([]byte, i+1-len(s.patable))...)
+		}
+		s.patable[i] = value
+		return
+	}
+	if addr, ok := registerAddr(name); ok {
+		if s.regs == nil {
+			s.regs = make(map[byte]byte)
+		}
+		s.regs[addr] = value
+	}
+}
+
+// parseText reads the export line by line. A register name is paired with
+// the last byte-sized 0x-prefixed number that follows it on the same line,
+// before the next name, so that an address column between the name and the
+// value ("IOCFG0 0x02 0x06") is skipped. Wider numbers are never values.
+func (s *smartRFBuilder) parseText(text string) error {
+	for _, line := range strings.Split(text, "\n") {
+		name, value := "", ""
+		for _, tok := range smartRFTokens.FindAllString(line, -1) {
+			if !strings.HasPrefix(tok, "0x") && !strings.HasPrefix(tok, "0X") {
+				if err := s.setText(name, value); err != nil {
+					return err
+				}
+				name, value = tok, ""
+				continue
+			}
+			if len(tok) <= 4 {
+				value = tok
+			}
+		}
+		if err := s.setText(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setText records a name and value pair found by parseText, if complete.
+func (s *smartRFBuilder) setText(name, value string) error {
+	if name == "" || value == "" {
+		return nil
+	}
+	v, err := strconv.ParseUint(value[2:], 16, 8)
+	if err != nil {
+		return fmt.Errorf("register %s: invalid value %q", name, value)
+	}
+	s.set(name, byte(v))
+	return nil
+}
+
+// parseXML reads <Register><Name>IOCFG2</Name><Value>0x29</Value></Register>
+// elements.
+func (s *smartRFBuilder) parseXML(text string) error {
+	dec := xml.NewDecoder(strings.NewReader(text))
+	var elem, name, value string
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid SmartRF XML export: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			elem = t.Name.Local
+			if strings.EqualFold(elem, "Register") {
+				name, value = "", ""
+			}
+		case xml.CharData:
+			switch strings.ToLower(elem) {
+			case "name":
+				name += strings.TrimSpace(string(t))
+			case "value":
+				value += strings.TrimSpace(string(t))
+			}
+		case xml.EndElement:
+			elem = ""
+			if strings.EqualFold(t.Name.Local, "Register") && name != "" {
+				v, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimPrefix(value, "0x"), "0X"), 16, 8)
+				if err != nil {
+					return fmt.Errorf("register %s: invalid value %q", name, value)
+				}
+				s.set(name, byte(v))
+			}
+		}
+	}
+}
+
+func (s *smartRFBuilder) settings() *Settings {
+	out := &Settings{PATable: s.patable}
+	for addr, value := range s.regs {
+		out.Registers = append(out.Registers, RegisterValue{Addr: addr, Value: value})
+	}
+	sort.Slice(out.Registers, func(i, j int) bool {
+		return out.Registers[i].Addr < out.Registers[j].Addr
+	})
+	return out
+}
+
+// Snapshot returns the complete configuration obtained by applying s on top
+// of the chip reset defaults.
+func (s *Settings) Snapshot() *Snapshot {
+	snap := DefaultSnapshot()
+	for _, rv := range s.Registers {
+		snap.Registers[rv.Addr] = rv.Value
+	}
+	copy(snap.PATable[:], s.PATable)
+	return snap
+}
+
+// ApplySettings puts the chip in IDLE and writes the registers and PA table
+// listed in s. Runs of consecutive registers are written in one burst.
+func (d *Device) ApplySettings(s *Settings) error {
+	if err := d.SpiStrobe(SIDLE); err != nil {
+		return err
+	}
+	regs := s.Registers
+	for len(regs) > 0 {
+		n := 1
+		for n < len(regs) && regs[n].Addr == regs[0].Addr+byte(n) {
+			n++
+		}
+		burst := make([]byte, n)
+		for i := range burst {
+			burst[i] = regs[i].Value
+		}
+		if err := d.WriteBurstRegister(regs[0].Addr, burst); err != nil {
+			return fmt.Errorf("failed to write %s: %w", RegisterName(regs[0].Addr), err)
+		}
+		regs = regs[n:]
+	}
+	if len(s.PATable) > 0 {
+		if err := d.WriteBurstRegister(PATABLE, s.PATable); err != nil {
+			return fmt.Errorf("failed to write PATABLE: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package cc1101
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSmartRF(t *testing.T) {
+	want := []RegisterValue{{IOCFG2, 0x29}, {IOCFG0, 0x06}, {PKTLEN, 0xFF}}
+	tests := []struct {
+		name   string
+		export string
+	}{
+		{"C header", `
+// Address Config = No address check
+#define SMARTRF_SETTING_IOCFG2           0x29
+#define SMARTRF_SETTING_IOCFG0           0x06
+#define SMARTRF_SETTING_PKTLEN           0xFF
+`},
+		{"settings array", `
+static const registerSetting_t preferredSettings[] =
+{
+  {CC1101_IOCFG2,           0x29},
+  {CC1101_IOCFG0,           0x06},
+  {CC1101_PKTLEN,           0xFF},
+};
+`},
+		{"settings array, one line", `{CC1101_IOCFG2, 0x29}, {CC1101_IOCFG0, 0x06}, {CC1101_PKTLEN, 0xFF}`},
+		{"halRfWriteReg", `
+halRfWriteReg(IOCFG2,0x29);  //GDO2 Output Pin Configuration
+halRfWriteReg(IOCFG0,0x06);  //GDO0 Output Pin Configuration
+halRfWriteReg(PKTLEN,0xFF);  //Packet Length
+`},
+		{"XML", `<?xml version="1.0" encoding="UTF-8"?>
+<registersettings>
+  <Register><Name>IOCFG2</Name><Value>0x29</Value></Register>
+  <Register><Name>IOCFG0</Name><Value>0x06</Value></Register>
+  <Register><Name>PKTLEN</Name><Value>0xFF</Value></Register>
+</registersettings>
+`},
+		{"name value table", `
+IOCFG2 0x29
+IOCFG0 0x06
+PKTLEN 0xFF
+`},
+		{"name address value table", `
+IOCFG2         0x0000      0x29        GDO2 Output Pin Configuration
+IOCFG0         0x0002      0x06        GDO0 Output Pin Configuration
+PKTLEN         0x0006      0xFF        Packet Length
+`},
+		{"name short address value table", `
+IOCFG2 0x00 0x29
+IOCFG0 0x02 0x06
+PKTLEN 0x06 0xFF
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseSmartRF(strings.NewReader(tt.export))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(s.Registers, want) {
+				t.Errorf("registers = %v, want %v", s.Registers, want)
+			}
+		})
+	}
+}
+
+func TestParseSmartRFPATable(t *testing.T) {
+	s, err := ParseSmartRF(strings.NewReader(`
+#define SMARTRF_SETTING_FREND0 0x11
+#define SMARTRF_SETTING_PA_TABLE0 0x00
+#define SMARTRF_SETTING_PA_TABLE1 0xC0
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{0x00, 0xC0}; !reflect.DeepEqual(s.PATable, want) {
+		t.Errorf("PA table = % X, want % X", s.PATable, want)
+	}
+}
+
+func TestParseSmartRFEmpty(t *testing.T) {
+	if _, err := ParseSmartRF(strings.NewReader("no settings here")); err == nil {
+		t.Error("ParseSmartRF accepted an export without settings")
+	}
+}
@@ -0,0 +1,136 @@
+package cc1101
+
+import "fmt"
+
+// Snapshot is the complete radio configuration: the configuration registers
+// 0x00-0x2E and the PA power table.
+type Snapshot struct {
+	Registers [CFG_REGISTER]byte `json:"registers"`
+	PATable   [PATABLE_SIZE]byte `json:"patable"`
+}
+
+// PATABLE content after a reset.
+var resetPATable = [PATABLE_SIZE]byte{0xC6}
+
+// DefaultSnapshot returns the register and PATABLE values the chip has after
+// a reset.
+func DefaultSnapshot() *Snapshot {
+	return &Snapshot{Registers: resetDefaults, PATable: resetPATable}
+}
+
+// Snapshot reads every configuration register and the PATABLE from the chip.
+func (d *Device) Snapshot() (*Snapshot, error) {
+	var s Snapshot
+	if err := d.readSnapshot(&s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// readSnapshot is Snapshot reading into s, without allocating.
+func (d *Device) readSnapshot(s *Snapshot) error {
+	if err := d.ReadBurstRegisterInto(IOCFG2, s.Registers[:]); err != nil {
+		return fmt.Errorf("failed to read registers: %w", err)
+	}
+	if err := d.ReadBurstRegisterInto(PATABLE, s.PATable[:]); err != nil {
+		return fmt.Errorf("failed to read PATABLE: %w", err)
+	}
+	return nil
+}
+
+// Restore puts the chip in IDLE and writes back every register and the
+// PATABLE held in s.
+func (d *Device) Restore(s *Snapshot) error {
+	if err := d.SpiStrobe(SIDLE); err != nil {
+		return err
+	}
+	if err := d.WriteBurstRegister(IOCFG2, s.Registers[:]); err != nil {
+		return fmt.Errorf("failed to write registers: %w", err)
+	}
+	if err := d.WriteBurstRegister(PATABLE, s.PATable[:]); err != nil {
+		return fmt.Errorf("failed to write PATABLE: %w", err)
+	}
+	d.mu.Lock()
+	d.shadowKnown = true
+	d.mu.Unlock()
+	return nil
+}
+
+// LoadShadow reads the configuration from the chip and takes it as the one
+// written by the driver, for a chip configured before New that is not to
+// be reset or reprogrammed. Any corruption already present is adopted too.
+func (d *Device) LoadShadow() error {
+	d.cfgMu.Lock()
+	defer d.cfgMu.Unlock()
+
+	var s Snapshot
+	if err := d.readSnapshot(&s); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	d.regs = s.Registers
+	d.patable = s.PATable
+	d.shadowKnown = true
+	d.mu.Unlock()
+	return nil
+}
+
+// Change is one register field that differs between two snapshots. Old and
+// New are the field values shifted down to bit 0.
+type Change struct {
+	Addr     byte
+	Register string
+	Field    string
+	Old, New byte
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s.%s: 0x%02X -> 0x%02X", c.Register, c.Field, c.Old, c.New)
+}
+
+// Diff lists, field by field, what changed from a to b. Fields are those of
+// the typed register model; changes to bits outside them are reported as
+// the "reserved" field.
+func Diff(a, b *Snapshot) []Change {
+	var changes []Change
+	for addr := range a.Registers {
+		before, after := a.Registers[addr], b.Registers[addr]
+		if before == after {
+			continue
+		}
+		name := registerNames[addr]
+		layout := registerLayout(byte(addr))
+		for _, f := range layout {
+			if before&f.mask != after&f.mask {
+				changes = append(changes, Change{
+					Addr:     byte(addr),
+					Register: name,
+					Field:    f.name,
+					Old:      f.value(before),
+					New:      f.value(after),
+				})
+			}
+		}
+		if reserved := reservedMask(layout); before&reserved != after&reserved {
+			changes = append(changes, Change{
+				Addr:     byte(addr),
+				Register: name,
+				Field:    "reserved",
+				Old:      before & reserved,
+				New:      after & reserved,
+			})
+		}
+	}
+	for i := range a.PATable {
+		if a.PATable[i] != b.PATable[i] {
+			changes = append(changes, Change{
+				Addr:     PATABLE,
+				Register: "PATABLE",
+				Field:    fmt.Sprintf("PATABLE[%d]", i),
+				Old:      a.PATable[i],
+				New:      b.PATable[i],
+			})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,62 @@
+package cc1101
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a := DefaultSnapshot()
+	if changes := Diff(a, a); len(changes) != 0 {
+		t.Errorf("Diff of a snapshot with itself = %v", changes)
+	}
+
+	b := *a
+	b.Registers[MDMCFG2] = 0x30 | a.Registers[MDMCFG2]&^0x70 // OOK
+	b.Registers[FIFOTHR] |= 0x80                              // reserved bit
+	b.Registers[SYNC1] = 0x12
+	b.Registers[FREQ2] = 0x21
+	b.Registers[TEST0] ^= 0x02
+	b.PATable[1] = Power_0dBm
+
+	want := []Change{
+		{FIFOTHR, "FIFOTHR", "reserved", 0x00, 0x80},
+		{SYNC1, "SYNC1", "SYNC1", 0xD3, 0x12},
+		{FREQ2, "FREQ2", "FREQ[21:16]", 0x1E, 0x21},
+		{MDMCFG2, "MDMCFG2", "MOD_FORMAT", byte(Modulation2FSK), byte(ModulationOOK)},
+		{TEST0, "TEST0", "VCO_SEL_CAL_EN", 1, 0},
+		{PATABLE, "PATABLE", "PATABLE[1]", 0x00, Power_0dBm},
+	}
+	if got := Diff(a, &b); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestRegisterLayout(t *testing.T) {
+	for addr := byte(0); addr < CFG_REGISTER; addr++ {
+		layout := registerLayout(addr)
+		var all byte
+		for _, f := range layout {
+			if f.mask == 0 {
+				t.Errorf("%s.%s occupies no bits", RegisterName(addr), f.name)
+			}
+			if all&f.mask != 0 {
+				t.Errorf("%s.%s overlaps another field", RegisterName(addr), f.name)
+			}
+			all |= f.mask
+		}
+		// The fields are exactly the bits the typed model encodes.
+		if r := newRegister(addr); r != nil {
+			r.Decode(0xFF)
+			if got := r.Encode(); got != all {
+				t.Errorf("%s: fields cover 0x%02X, Encode(Decode(0xFF)) = 0x%02X", RegisterName(addr), all, got)
+			}
+			if r.Addr() != addr {
+				t.Errorf("newRegister(0x%02X) is the model of 0x%02X", addr, r.Addr())
+			}
+		}
+		if got, ok := registerAddr(RegisterName(addr)); !ok || got != addr {
+			t.Errorf("registerAddr(%q) = 0x%02X, %v", RegisterName(addr), got, ok)
+		}
+	}
+}
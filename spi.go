@@ -1,9 +1,23 @@
 package cc1101
 
 import (
+	"errors"
 	"time"
 )
 
+// Size of the device SPI buffers: a header byte and a full FIFO, the
+// longest transfer the driver makes. Longer bursts are split into several
+// Tx calls within the same transaction.
+const spiBufferSize = 1 + FIFOBUFFER
+
+// Time allowed for the chip to pull MISO low once CS is asserted. The
+// crystal starts in less than 1 ms, even from SLEEP.
+const chipReadyTimeout = 10 * time.Millisecond
+
+// ErrChipNotReady is returned when MISO stays high after CS is asserted,
+// as with a missing or unpowered module on a pulled-up MISO line.
+var ErrChipNotReady = errors.New("chip not ready: MISO stayed high")
+
 func (d *Device) EnableCS() {
 	d.cs(false)
 }
@@ -11,109 +25,177 @@ func (d *Device) DisableCS() {
 	d.cs(true)
 }
 
-
 func (d *Device) Reset() error {
+	d.lock()
 	d.EnableCS()
 	time.Sleep(10 * time.Microsecond)
 	d.DisableCS()
 	time.Sleep(40 * time.Microsecond)
+	d.unlock()
 
 	err := d.SpiStrobe(SRES)
 	if err != nil {
 		return err
 	}
 	time.Sleep(1 * time.Millisecond)
+	d.mu.Lock()
+	d.regs = resetDefaults
+	d.patable = resetPATable
+	d.shadowKnown = true
+	d.mu.Unlock()
 
 	return nil
 }
 
 func (d *Device) ReadSingleRegister(addr byte) (byte, error) {
-	var temp = addr | CC1101_READSINGLE
-	var readBuffer = []byte{0x00}
-	var writeBuffer = []byte{temp}
-
-	d.EnableCS()
-	for d.miso.Get() != false {
-		time.Sleep(1 * time.Microsecond)
-	}
-	if err := d.bus.Tx(writeBuffer, nil); err != nil {
-		d.DisableCS()
+	var value [1]byte
+	if _, err := d.transfer(addr|CC1101_READSINGLE, nil, value[:]); err != nil {
 		return 0, err
 	}
-	if err := d.bus.Tx([]byte{0x00}, readBuffer); err != nil {
-		d.DisableCS()
-		return 0, err
-	}
-	d.DisableCS()
-	return readBuffer[0], nil
+	return value[0], nil
 }
 
 func (d *Device) ReadBurstRegister(addr byte, length int) ([]byte, error) {
-	var temp = addr | CC1101_READBURST
 	data := make([]byte, length)
-	d.EnableCS()
-	for d.miso.Get() != false {
-		time.Sleep(1 * time.Microsecond)
-	}
-	if err := d.bus.Tx([]byte{temp}, nil); err != nil {
-		d.DisableCS()
-		return nil, err
-	}
-	if err := d.bus.Tx(make([]byte, length), data); err != nil {
-		d.DisableCS()
+	if err := d.ReadBurstRegisterInto(addr, data); err != nil {
 		return nil, err
 	}
-	d.DisableCS()
-
 	return data, nil
 }
 
+// ReadBurstRegisterInto is ReadBurstRegister reading into a caller-supplied
+// buffer, without allocating.
+func (d *Device) ReadBurstRegisterInto(addr byte, data []byte) error {
+	_, err := d.transfer(addr|CC1101_READBURST, nil, data)
+	return err
+}
+
 func (d *Device) WriteSingleRegister(addr, value byte) error {
-	d.EnableCS()
-	for d.miso.Get() != false {
-		time.Sleep(1 * time.Microsecond)
+	_, err := d.transfer(addr, []byte{value}, nil)
+	return err
+}
+
+func (d *Device) SpiStrobe(strobe byte) error {
+	_, err := d.transfer(strobe, nil, nil)
+	return err
+}
+
+func (d *Device) WriteBurstRegister(addr byte, data []byte) error {
+	_, err := d.transfer(addr|CC1101_WRITEBURST, data, nil)
+	return err
+}
+
+// transfer runs one SPI transaction: the header byte followed by the bytes
+// of w, or by len(r) dummy bytes whose replies are copied to r. Transfers
+// that fit the device buffers use a single Tx call. It returns the status
+// byte sent back for the header, or for the last byte written, and updates
+// the register shadow for configuration register and PATABLE writes.
+func (d *Device) transfer(header byte, w, r []byte) (StatusByte, error) {
+	n := len(w)
+	if r != nil {
+		n = len(r)
 	}
-	if err := d.bus.Tx([]byte{addr}, nil); err != nil {
-		d.DisableCS()
-		return err
+
+	d.lock()
+	defer d.unlock()
+
+	if d.tracer != nil {
+		d.trace = Transaction{Start: time.Now(), MOSI: d.trace.MOSI[:0], MISO: d.trace.MISO[:0]}
 	}
-	if err := d.bus.Tx([]byte{value}, nil); err != nil {
+	d.EnableCS()
+	if !d.waitChipReady() {
 		d.DisableCS()
-		return err
+		if d.tracer != nil {
+			d.trace.MOSI = append(d.trace.MOSI, header)
+		}
+		d.traceDone(ErrChipNotReady)
+		return d.status, ErrChipNotReady
 	}
+
+	d.txBuf[0] = header
+	start, done := 1, 0
+	for {
+		chunk := min(n-done, spiBufferSize-start)
+		tx, rx := d.txBuf[:start+chunk], d.rxBuf[:start+chunk]
+		if w != nil {
+			copy(tx[start:], w[done:done+chunk])
+		} else {
+			clear(tx[start:])
+		}
+		err := d.bus.Tx(tx, rx)
+		if d.tracer != nil {
+			d.trace.MOSI = append(d.trace.MOSI, tx...)
+			d.trace.MISO = append(d.trace.MISO, rx...)
+		}
+		if err != nil {
+			d.DisableCS()
+			d.traceDone(err)
+			return d.status, err
+		}
+		if start == 1 {
+			d.status = StatusByte(rx[0])
+		}
+		if r != nil {
+			copy(r[done:], rx[start:])
+		} else if chunk > 0 {
+			d.status = StatusByte(rx[len(rx)-1])
+		}
+		done += chunk
+		start = 0
+		if done >= n {
+			break
+		}
+	}
+
 	d.DisableCS()
-	return nil
+	d.traceDone(nil)
+	if addr := header & 0x3F; header&CC1101_READSINGLE == 0 && addr < CFG_REGISTER {
+		copy(d.regs[addr:], w)
+	} else if header&CC1101_READSINGLE == 0 && addr == PATABLE {
+		// Writes start at index 0: the table pointer is reset when CS
+		// is released.
+		copy(d.patable[:], w)
+	}
+	return d.status, nil
 }
 
-func (d *Device) SpiStrobe(strobe byte) error {
-	d.EnableCS()
-	for d.miso.Get() != false {
-		time.Sleep(1 * time.Microsecond)
+// waitChipReady waits, with CS asserted, for the chip to pull MISO low
+// (CHIP_RDYn). It reports false after chipReadyTimeout.
+func (d *Device) waitChipReady() bool {
+	if !d.miso() {
+		return true
 	}
-	if err := d.bus.Tx([]byte{strobe}, nil); err != nil {
-		d.DisableCS()
-		return err
+	deadline := time.Now().Add(chipReadyTimeout)
+	for d.miso() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(1 * time.Microsecond)
 	}
-	d.DisableCS()
-	return nil
+	return true
 }
 
-func (d *Device) WriteBurstRegister(addr byte, data []byte) error {
-	temp := addr | CC1101_WRITEBURST
-	d.EnableCS()
-	for d.miso.Get() != false {
-		time.Sleep(1 * time.Microsecond)
+// traceDone passes the transaction recorded by transfer to the tracer.
+func (d *Device) traceDone(err error) {
+	if d.tracer == nil {
+		return
 	}
-	if err := d.bus.Tx([]byte{temp}, nil); err != nil {
-		d.DisableCS()
-		return err
+	d.trace.Duration = time.Since(d.trace.Start)
+	d.trace.Err = err
+	d.tracer.Trace(&d.trace)
+}
+
+// lock takes the device lock, then the shared bus lock if any.
+func (d *Device) lock() {
+	d.mu.Lock()
+	if d.busLock != nil {
+		d.busLock.Lock()
 	}
-	for _, byteData := range data {
-		if err := d.bus.Tx([]byte{byteData}, nil); err != nil {
-			d.DisableCS()
-			return err
-		}
+}
+
+func (d *Device) unlock() {
+	if d.busLock != nil {
+		d.busLock.Unlock()
 	}
-	d.DisableCS()
-	return nil
-}
\ No newline at end of file
+	d.mu.Unlock()
+}
@@ -0,0 +1,84 @@
+package cc1101
+
+import "testing"
+
+// Register I/O and the packet paths must not allocate once the buffers they
+// reuse have been set up, so that they can run in a receive loop on a
+// microcontroller without garbage.
+
+func newBenchDevice() (*Device, *fakeChip) {
+	d, chip := newFakeDevice()
+	chip.discard = true
+	return d, chip
+}
+
+// allocPaths returns the operations checked by TestZeroAllocs and the
+// benchmarks, each run against a fresh fake chip.
+func allocPaths(tb testing.TB) map[string]func() {
+	fail := func(err error) {
+		if err != nil {
+			tb.Fatal(err)
+		}
+	}
+	paths := make(map[string]func())
+
+	d, _ := newBenchDevice()
+	paths["ReadSingleRegister"] = func() {
+		_, err := d.ReadSingleRegister(PKTLEN)
+		fail(err)
+	}
+	paths["WriteSingleRegister"] = func() {
+		fail(d.WriteSingleRegister(PKTLEN, 0x3D))
+	}
+	var burst [CFG_REGISTER]byte
+	paths["ReadBurstRegisterInto"] = func() {
+		fail(d.ReadBurstRegisterInto(IOCFG2, burst[:]))
+	}
+	paths["WriteBurstRegister"] = func() {
+		fail(d.WriteBurstRegister(IOCFG2, burst[:]))
+	}
+
+	dTx, _ := newBenchDevice()
+	packet := make([]byte, 32)
+	paths["SendData"] = func() {
+		fail(dTx.SendData(packet))
+	}
+
+	dRx, chip := newBenchDevice()
+	// Variable length with appended RSSI and LQI, the reset defaults.
+	received := append([]byte{32}, make([]byte, 32)...)
+	received = append(received, 0x80, 0x80)
+	p := Packet{Data: make([]byte, 0, FIFOBUFFER)}
+	paths["ReceiveDataInto"] = func() {
+		chip.receive(received...)
+		fail(dRx.ReceiveDataInto(&p))
+	}
+	return paths
+}
+
+func TestZeroAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates")
+	}
+	for name, fn := range allocPaths(t) {
+		if n := testing.AllocsPerRun(100, fn); n != 0 {
+			t.Errorf("%s: %.1f allocations per call, want 0", name, n)
+		}
+	}
+}
+
+func benchmarkPath(b *testing.B, name string) {
+	fn := allocPaths(b)[name]
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fn()
+	}
+}
+
+func BenchmarkReadSingleRegister(b *testing.B)    { benchmarkPath(b, "ReadSingleRegister") }
+func BenchmarkWriteSingleRegister(b *testing.B)   { benchmarkPath(b, "WriteSingleRegister") }
+func BenchmarkReadBurstRegisterInto(b *testing.B) { benchmarkPath(b, "ReadBurstRegisterInto") }
+func BenchmarkWriteBurstRegister(b *testing.B)    { benchmarkPath(b, "WriteBurstRegister") }
+func BenchmarkSendData(b *testing.B)              { benchmarkPath(b, "SendData") }
+func BenchmarkReceiveDataInto(b *testing.B)       { benchmarkPath(b, "ReceiveDataInto") }
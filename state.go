@@ -0,0 +1,243 @@
+package cc1101
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MarcState is a main radio control state read from MARCSTATE.
+type MarcState byte
+
+var marcStateNames = [...]string{
+	"SLEEP", "IDLE", "XOFF", "VCOON_MC", "REGON_MC", "MANCAL", "VCOON", "REGON",
+	"STARTCAL", "BWBOOST", "FS_LOCK", "IFADCON", "ENDCAL", "RX", "RX_END", "RX_RST",
+	"TXRX_SWITCH", "RXFIFO_OVERFLOW", "FSTXON", "TX", "TX_END", "RXTX_SWITCH", "TXFIFO_UNDERFLOW",
+}
+
+func (s MarcState) String() string {
+	if int(s) < len(marcStateNames) {
+		return marcStateNames[s]
+	}
+	return fmt.Sprintf("MARCSTATE(0x%02X)", byte(s))
+}
+
+// Time allowed by Transition for the chip to reach the target state. A
+// calibration takes about 0.8 ms and XOSC start-up less than 1 ms.
+const transitionTimeout = 10 * time.Millisecond
+
+// TransitionError reports a state transition that did not complete. Path
+// holds the distinct states observed, in order.
+type TransitionError struct {
+	Target MarcState
+	Path   []MarcState
+	Err    error
+}
+
+func (e *TransitionError) Error() string {
+	path := make([]string, len(e.Path))
+	for i, s := range e.Path {
+		path[i] = s.String()
+	}
+	return fmt.Sprintf("transition to %s failed: %v (states: %s)", e.Target, e.Err, strings.Join(path, " -> "))
+}
+
+func (e *TransitionError) Unwrap() error { return e.Err }
+
+// MarcState reads the current main radio control state.
+func (d *Device) MarcState() (MarcState, error) {
+	state, err := d.ReadStatusRegister(MARCSTATE)
+	if err != nil {
+		return 0, err
+	}
+	return MarcState(state & MARCSTATE_MASK), nil
+}
+
+// Transition moves the chip to IDLE, RX, TX or FSTXON and waits until
+// MARCSTATE confirms it. FIFO overflow and underflow states are cleared
+// with SFRX/SFTX first, and the synthesizer is calibrated with SCAL before
+// leaving IDLE when automatic calibration is off.
+//
+// A packet already in the TX FIFO may be sent before MARCSTATE is read, so
+// a transition to TX also succeeds once the chip reports TX_END or has
+// drained the TX FIFO and reached its TXOFF_MODE state. It fails with
+// ErrTxUnderflow if the FIFO runs empty during the packet.
+func (d *Device) Transition(target MarcState) error {
+	return d.TransitionWithin(target, transitionTimeout)
+}
+
+// TransitionWithin is Transition with a custom timeout, e.g. to cover a
+// clear channel assessment that delays TX.
+func (d *Device) TransitionWithin(target MarcState, timeout time.Duration) error {
+	var strobe byte
+	switch target {
+	case MARCSTATE_IDLE:
+		strobe = SIDLE
+	case MARCSTATE_RX:
+		strobe = SRX
+	case MARCSTATE_TX:
+		strobe = STX
+	case MARCSTATE_FSTXON:
+		strobe = SFSTXON
+	default:
+		return fmt.Errorf("unsupported transition target %s", target)
+	}
+
+	t := &transition{d: d, target: target, deadline: time.Now().Add(timeout)}
+	state, err := t.observe()
+	if err != nil {
+		return err
+	}
+	if state == target {
+		return nil
+	}
+
+	// Clear the FIFO error states: SFRX and SFTX are only accepted there
+	// or in IDLE, and return the chip to IDLE.
+	switch state {
+	case MARCSTATE_RX_OVERFLOW:
+		if err := t.strobe(SFRX, MARCSTATE_IDLE); err != nil {
+			return err
+		}
+	case MARCSTATE_TX_UNDERFLOW:
+		if err := t.strobe(SFTX, MARCSTATE_IDLE); err != nil {
+			return err
+		}
+	}
+	if target == MARCSTATE_IDLE {
+		return t.strobe(SIDLE, target)
+	}
+
+	// Only RX to TX and FSTXON to RX or TX are direct; anything else goes
+	// through IDLE.
+	direct := t.last == MARCSTATE_IDLE ||
+		t.last == MARCSTATE_RX && target == MARCSTATE_TX ||
+		t.last == MARCSTATE_FSTXON && target != MARCSTATE_FSTXON
+	if !direct {
+		if err := t.strobe(SIDLE, MARCSTATE_IDLE); err != nil {
+			return err
+		}
+	}
+
+	var mcsm0 Mcsm0
+	mcsm0.Decode(d.shadow(MCSM0))
+	if mcsm0.AutoCal == AutoCalNever && t.last == MARCSTATE_IDLE {
+		if err := t.strobe(SCAL, MARCSTATE_IDLE); err != nil {
+			return err
+		}
+	}
+	if target == MARCSTATE_TX {
+		return t.strobeTX()
+	}
+	return t.strobe(strobe, target)
+}
+
+// SetRx puts the chip in RX.
+func (d *Device) SetRx() error {
+	return d.Transition(MARCSTATE_RX)
+}
+
+// SetTx puts the chip in TX.
+func (d *Device) SetTx() error {
+	return d.Transition(MARCSTATE_TX)
+}
+
+// SetIdle puts the chip in IDLE.
+func (d *Device) SetIdle() error {
+	return d.Transition(MARCSTATE_IDLE)
+}
+
+// transition records the states seen while driving the chip to target.
+type transition struct {
+	d        *Device
+	target   MarcState
+	deadline time.Time
+	path     []MarcState
+	last     MarcState
+}
+
+func (t *transition) observe() (MarcState, error) {
+	state, err := t.d.MarcState()
+	if err != nil {
+		return 0, t.fail(err)
+	}
+	if len(t.path) == 0 || state != t.last {
+		t.path = append(t.path, state)
+	}
+	t.last = state
+	return state, nil
+}
+
+// strobe sends a command strobe and waits until the chip reports want.
+func (t *transition) strobe(strobe byte, want MarcState) error {
+	if err := t.d.SpiStrobe(strobe); err != nil {
+		return t.fail(err)
+	}
+	for {
+		state, err := t.observe()
+		if err != nil {
+			return err
+		}
+		if state == want {
+			return nil
+		}
+		if time.Now().After(t.deadline) {
+			return t.fail(fmt.Errorf("timeout waiting for %s", want))
+		}
+		time.Sleep(10 * time.Microsecond)
+	}
+}
+
+// strobeTX sends STX and waits for TX, or for the end of a packet that was
+// sent before MARCSTATE could be read.
+func (t *transition) strobeTX() error {
+	queued, _, err := t.d.TxBytes()
+	if err != nil {
+		return t.fail(err)
+	}
+	var m Mcsm1
+	m.Decode(t.d.shadow(MCSM1))
+	after := MarcState(MARCSTATE_IDLE)
+	switch m.TxOffMode {
+	case OffModeFSTXON:
+		after = MARCSTATE_FSTXON
+	case OffModeTX:
+		after = MARCSTATE_TX
+	case OffModeRX:
+		after = MARCSTATE_RX
+	}
+
+	if err := t.d.SpiStrobe(STX); err != nil {
+		return t.fail(err)
+	}
+	for {
+		state, err := t.observe()
+		if err != nil {
+			return err
+		}
+		switch {
+		case state == MARCSTATE_TX || state == MARCSTATE_TX_END || state == MARCSTATE_RXTX_SWITCH:
+			return nil
+		case state == MARCSTATE_TX_UNDERFLOW:
+			return t.fail(ErrTxUnderflow)
+		case state == after && queued > 0:
+			// Back in TXOFF_MODE: the packet was sent if the FIFO
+			// drained, otherwise TX has not started yet.
+			n, _, err := t.d.TxBytes()
+			if err != nil {
+				return t.fail(err)
+			}
+			if n == 0 {
+				return nil
+			}
+		}
+		if time.Now().After(t.deadline) {
+			return t.fail(fmt.Errorf("timeout waiting for %s", MarcState(MARCSTATE_TX)))
+		}
+		time.Sleep(10 * time.Microsecond)
+	}
+}
+
+func (t *transition) fail(err error) error {
+	return &TransitionError{Target: t.target, Path: t.path, Err: err}
+}
@@ -0,0 +1,155 @@
+package cc1101
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// strobes returns the command strobes recorded, by name.
+func strobes(r *Recorder) []string {
+	var names []string
+	for _, t := range r.Transactions() {
+		if t.Op() == "strobe" {
+			names = append(names, t.Name())
+		}
+	}
+	return names
+}
+
+func TestTransitionPaths(t *testing.T) {
+	tests := []struct {
+		from    byte
+		target  MarcState
+		strobes []string
+	}{
+		{MARCSTATE_IDLE, MARCSTATE_RX, []string{"SRX"}},
+		{MARCSTATE_RX, MARCSTATE_RX, nil},
+		{MARCSTATE_RX, MARCSTATE_TX, []string{"STX"}},
+		{MARCSTATE_TX, MARCSTATE_RX, []string{"SIDLE", "SRX"}},
+		{MARCSTATE_RX, MARCSTATE_FSTXON, []string{"SIDLE", "SFSTXON"}},
+		{MARCSTATE_FSTXON, MARCSTATE_RX, []string{"SRX"}},
+		{MARCSTATE_RX, MARCSTATE_IDLE, []string{"SIDLE"}},
+		{MARCSTATE_RX_OVERFLOW, MARCSTATE_RX, []string{"SFRX", "SRX"}},
+		{MARCSTATE_TX_UNDERFLOW, MARCSTATE_IDLE, []string{"SFTX", "SIDLE"}},
+	}
+	for _, tt := range tests {
+		d, chip := newFakeDevice()
+		if err := d.SetAutoCal(AutoCalFromIdle); err != nil {
+			t.Fatal(err)
+		}
+		chip.state = tt.from
+		rec := NewRecorder(0)
+		d.SetTracer(rec)
+		if err := d.Transition(tt.target); err != nil {
+			t.Errorf("%s -> %s: %v", MarcState(tt.from), tt.target, err)
+			continue
+		}
+		if got := strobes(rec); !reflect.DeepEqual(got, tt.strobes) {
+			t.Errorf("%s -> %s: strobes %v, want %v", MarcState(tt.from), tt.target, got, tt.strobes)
+		}
+		if MarcState(chip.state) != tt.target {
+			t.Errorf("%s -> %s: chip in %s", MarcState(tt.from), tt.target, MarcState(chip.state))
+		}
+	}
+}
+
+// FS_AUTOCAL is 0 after reset: SCAL is strobed before leaving IDLE.
+func TestTransitionCalibrates(t *testing.T) {
+	d, chip := newFakeDevice()
+	chip.state = MARCSTATE_RX
+	rec := NewRecorder(0)
+	d.SetTracer(rec)
+	if err := d.Transition(MARCSTATE_FSTXON); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strobes(rec), []string{"SIDLE", "SCAL", "SFSTXON"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("strobes %v, want %v", got, want)
+	}
+}
+
+func TestTransitionTimeout(t *testing.T) {
+	tests := []struct {
+		from byte
+		path []MarcState
+	}{
+		{MARCSTATE_IDLE, []MarcState{MARCSTATE_IDLE}},
+		{MARCSTATE_RX_OVERFLOW, []MarcState{MARCSTATE_RX_OVERFLOW, MARCSTATE_IDLE}},
+	}
+	for _, tt := range tests {
+		d, chip := newFakeDevice()
+		chip.state = tt.from
+		chip.onStrobe = func(s byte) bool { return s == SRX }
+		err := d.TransitionWithin(MARCSTATE_RX, time.Millisecond)
+		var terr *TransitionError
+		if !errors.As(err, &terr) {
+			t.Fatalf("from %s: error %v, want a TransitionError", MarcState(tt.from), err)
+		}
+		if terr.Target != MARCSTATE_RX || !reflect.DeepEqual(terr.Path, tt.path) {
+			t.Errorf("from %s: target %s, path %v; want RX, %v", MarcState(tt.from), terr.Target, terr.Path, tt.path)
+		}
+	}
+}
+
+// The fake sends a queued packet at STX, so MARCSTATE never shows TX.
+func TestTransitionTxPacketSent(t *testing.T) {
+	for _, mode := range []OffMode{OffModeIdle, OffModeFSTXON, OffModeRX} {
+		d, chip := newFakeDevice()
+		if err := d.SetTxOffMode(mode); err != nil {
+			t.Fatal(err)
+		}
+		if err := d.WriteBurstRegister(TXFIFO_SINGLE_BYTE, []byte{2, 0xAA, 0x55}); err != nil {
+			t.Fatal(err)
+		}
+		if err := d.SetTx(); err != nil {
+			t.Errorf("TXOFF_MODE %v: %v", mode, err)
+		}
+		if len(chip.packets()) != 1 {
+			t.Errorf("TXOFF_MODE %v: packet not sent", mode)
+		}
+	}
+}
+
+func TestTransitionTxUnderflow(t *testing.T) {
+	d, chip := newFakeDevice()
+	if err := d.WriteBurstRegister(TXFIFO_SINGLE_BYTE, []byte{10, 1}); err != nil {
+		t.Fatal(err)
+	}
+	chip.onStrobe = func(s byte) bool {
+		if s != STX {
+			return false
+		}
+		chip.txFIFO = chip.txFIFO[:0]
+		chip.state = MARCSTATE_TX_UNDERFLOW
+		return true
+	}
+	err := d.SetTx()
+	if !errors.Is(err, ErrTxUnderflow) {
+		t.Fatalf("SetTx = %v, want ErrTxUnderflow", err)
+	}
+	var terr *TransitionError
+	if !errors.As(err, &terr) || !reflect.DeepEqual(terr.Path, []MarcState{MARCSTATE_IDLE, MARCSTATE_TX_UNDERFLOW}) {
+		t.Errorf("SetTx = %v, want path IDLE -> TXFIFO_UNDERFLOW", err)
+	}
+}
+
+// With data queued and the chip still in IDLE, TX has not started.
+func TestTransitionTxNotStarted(t *testing.T) {
+	d, chip := newFakeDevice()
+	if err := d.WriteBurstRegister(TXFIFO_SINGLE_BYTE, []byte{1, 1}); err != nil {
+		t.Fatal(err)
+	}
+	chip.onStrobe = func(s byte) bool { return s == STX }
+	var terr *TransitionError
+	if err := d.TransitionWithin(MARCSTATE_TX, time.Millisecond); !errors.As(err, &terr) {
+		t.Errorf("TransitionWithin = %v, want a TransitionError", err)
+	}
+}
+
+func TestTransitionUnsupportedTarget(t *testing.T) {
+	d, _ := newFakeDevice()
+	if err := d.Transition(MARCSTATE_RX_OVERFLOW); err == nil {
+		t.Error("Transition to RXFIFO_OVERFLOW succeeded")
+	}
+}
@@ -0,0 +1,123 @@
+package cc1101
+
+import (
+	"errors"
+	"fmt"
+)
+
+// StatusByte is the chip status byte shifted out on MISO while the header
+// byte of every SPI transaction, and each byte written, is shifted in.
+type StatusByte byte
+
+// ChipState is the STATE field of the status byte, a summary of MARCSTATE.
+type ChipState byte
+
+const (
+	ChipStateIdle        ChipState = 0x00
+	ChipStateRX          ChipState = 0x01
+	ChipStateTX          ChipState = 0x02
+	ChipStateFSTXON      ChipState = 0x03
+	ChipStateCalibrate   ChipState = 0x04
+	ChipStateSettling    ChipState = 0x05 // PLL settling
+	ChipStateRxOverflow  ChipState = 0x06
+	ChipStateTxUnderflow ChipState = 0x07
+)
+
+func (s ChipState) String() string {
+	names := [...]string{"IDLE", "RX", "TX", "FSTXON", "CALIBRATE", "SETTLING", "RXFIFO_OVERFLOW", "TXFIFO_UNDERFLOW"}
+	return names[s&0x07]
+}
+
+// ChipReady reports whether CHIP_RDYn is low: the crystal is running and
+// the chip accepts commands.
+func (s StatusByte) ChipReady() bool {
+	return s&0x80 == 0
+}
+
+// State is the main state machine mode.
+func (s StatusByte) State() ChipState {
+	return ChipState(s>>4) & 0x07
+}
+
+// FIFOBytes is the number of bytes available in the RX FIFO after a read
+// transaction, or free in the TX FIFO after a write. 15 means 15 or more.
+func (s StatusByte) FIFOBytes() int {
+	return int(s & 0x0F)
+}
+
+func (s StatusByte) String() string {
+	ready := "ready"
+	if !s.ChipReady() {
+		ready = "not ready"
+	}
+	return fmt.Sprintf("%s, %s, %d FIFO bytes", ready, s.State(), s.FIFOBytes())
+}
+
+// LastStatus returns the status byte of the last SPI transaction, without
+// accessing the chip.
+func (d *Device) LastStatus() StatusByte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.status
+}
+
+// Status reads the status byte with an SNOP strobe. FIFOBytes is the number
+// of bytes in the RX FIFO.
+func (d *Device) Status() (StatusByte, error) {
+	return d.transfer(SNOP|CC1101_READSINGLE, nil, nil)
+}
+
+// Number of reads ReadStatusRegister makes before giving up.
+const statusReadRetries = 8
+
+// ErrStatusUnstable is returned when a status register never read the same
+// value twice in a row.
+var ErrStatusUnstable = errors.New("status register value not stable")
+
+// ReadStatusRegister reads a status register (PARTNUM to RCCTRL0_STATUS).
+// The errata note that a value changing during the SPI read can be
+// corrupted, so the register is read until two consecutive reads match.
+func (d *Device) ReadStatusRegister(addr byte) (byte, error) {
+	prev, err := d.ReadSingleRegister(addr)
+	if err != nil {
+		return 0, err
+	}
+	for i := 1; i < statusReadRetries; i++ {
+		value, err := d.ReadSingleRegister(addr)
+		if err != nil {
+			return 0, err
+		}
+		if value == prev {
+			return value, nil
+		}
+		prev = value
+	}
+	return 0, fmt.Errorf("register 0x%02X: %w", addr, ErrStatusUnstable)
+}
+
+// RxBytes reads the number of bytes in the RX FIFO and the overflow flag.
+func (d *Device) RxBytes() (int, bool, error) {
+	v, err := d.ReadStatusRegister(RXBYTES)
+	if err != nil {
+		return 0, false, err
+	}
+	return int(v & 0x7F), v&0x80 != 0, nil
+}
+
+// TxBytes reads the number of bytes in the TX FIFO and the underflow flag.
+func (d *Device) TxBytes() (int, bool, error) {
+	v, err := d.ReadStatusRegister(TXBYTES)
+	if err != nil {
+		return 0, false, err
+	}
+	return int(v & 0x7F), v&0x80 != 0, nil
+}
+
+// ReadRSSI reads the current received signal strength in dBm.
+func (d *Device) ReadRSSI() (float64, error) {
+	v, err := d.ReadStatusRegister(RSSI)
+	if err != nil {
+		return 0, err
+	}
+	return RSSIdBm(v), nil
+}
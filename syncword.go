@@ -0,0 +1,81 @@
+package cc1101
+
+import "fmt"
+
+// SetSyncWord sets the 16-bit sync word, SYNC1 holding the byte sent first.
+func (d *Device) SetSyncWord(word uint16) error {
+	return d.WriteBurstRegister(SYNC1, []byte{byte(word >> 8), byte(word)})
+}
+
+// GetSyncWord reads the sync word from SYNC1/SYNC0.
+func (d *Device) GetSyncWord() (uint16, error) {
+	var sync [2]byte
+	if err := d.ReadBurstRegisterInto(SYNC1, sync[:]); err != nil {
+		return 0, err
+	}
+	return uint16(sync[0])<<8 | uint16(sync[1]), nil
+}
+
+// SetRepeatedSync selects a 32-bit sync made of the sync word sent twice
+// (30/32 bits must match) or a single 16-bit sync word (16/16 bits). The
+// carrier-sense qualifier of the current SYNC_MODE is kept.
+func (d *Device) SetRepeatedSync(repeated bool) error {
+	return d.setMdmcfg2(func(m *Mdmcfg2) {
+		mode := Sync16of16
+		if repeated {
+			mode = Sync30of32
+		}
+		m.SyncMode = mode | m.SyncMode&syncModeCSFlag
+	})
+}
+
+// GetSyncMode reads the SYNC_MODE field of MDMCFG2.
+func (d *Device) GetSyncMode() (SyncMode, error) {
+	var m Mdmcfg2
+	if err := d.ReadRegister(&m); err != nil {
+		return 0, err
+	}
+	return m.SyncMode, nil
+}
+
+// SetPreambleLength sets the number of preamble bytes sent before the sync
+// word. The chip supports 2, 3, 4, 6, 8, 12, 16 and 24 bytes; other counts
+// are rounded up to the next supported one.
+func (d *Device) SetPreambleLength(bytes int) error {
+	setting, ok := preambleSetting(bytes)
+	if bytes < 1 || !ok {
+		return fmt.Errorf("invalid preamble length: %d bytes, must be 1 to %d", bytes, preambleBytes[len(preambleBytes)-1])
+	}
+	var m Mdmcfg1
+	return d.modify(&m, func() { m.NumPreamble = setting })
+}
+
+// GetPreambleLength reads the number of preamble bytes sent before the sync
+// word.
+func (d *Device) GetPreambleLength() (int, error) {
+	var m Mdmcfg1
+	if err := d.ReadRegister(&m); err != nil {
+		return 0, err
+	}
+	return preambleBytes[m.NumPreamble], nil
+}
+
+// SetPreambleQualityThreshold sets PKTCTRL1 PQT. Sync word detection is only
+// accepted once the preamble quality estimator reaches 4*pqt; 0 accepts any
+// sync word.
+func (d *Device) SetPreambleQualityThreshold(pqt byte) error {
+	if pqt > 7 {
+		return fmt.Errorf("invalid preamble quality threshold: %d", pqt)
+	}
+	var p Pktctrl1
+	return d.modify(&p, func() { p.PQT = pqt })
+}
+
+// GetPreambleQualityThreshold reads PKTCTRL1 PQT.
+func (d *Device) GetPreambleQualityThreshold() (byte, error) {
+	var p Pktctrl1
+	if err := d.ReadRegister(&p); err != nil {
+		return 0, err
+	}
+	return p.PQT, nil
+}
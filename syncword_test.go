@@ -0,0 +1,43 @@
+package cc1101
+
+import "testing"
+
+func TestSetPreambleLength(t *testing.T) {
+	d, chip := newFakeDevice()
+	tests := []struct {
+		bytes int
+		want  int // -1 for an error
+	}{
+		{-1, -1},
+		{0, -1},
+		{1, 2},
+		{2, 2},
+		{5, 6},
+		{24, 24},
+		{25, -1},
+	}
+	for _, tt := range tests {
+		before := chip.regs[MDMCFG1]
+		err := d.SetPreambleLength(tt.bytes)
+		if tt.want < 0 {
+			if err == nil {
+				t.Errorf("SetPreambleLength(%d) accepted", tt.bytes)
+			}
+			if chip.regs[MDMCFG1] != before {
+				t.Errorf("SetPreambleLength(%d) changed MDMCFG1 despite the error", tt.bytes)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("SetPreambleLength(%d): %v", tt.bytes, err)
+			continue
+		}
+		got, err := d.GetPreambleLength()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("SetPreambleLength(%d) set %d bytes, want %d", tt.bytes, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package cc1101
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Transaction is one SPI transaction, from CS assertion to release, as
+// passed to a Tracer.
+type Transaction struct {
+	Start    time.Time
+	Duration time.Duration
+
+	// Raw bus traffic: MOSI starts with the header byte, MISO with the
+	// status byte the chip returned for it.
+	MOSI []byte
+	MISO []byte
+
+	// Err is the error returned by the SPI bus, if any.
+	Err error
+}
+
+// Tracer receives every SPI transaction made by a Device. Trace is called
+// with the device locked: it must not use the Device, and t and its slices
+// are only valid during the call.
+type Tracer interface {
+	Trace(t *Transaction)
+}
+
+// SetTracer installs a tracer, or removes it when t is nil.
+func (d *Device) SetTracer(t Tracer) {
+	d.mu.Lock()
+	d.tracer = t
+	d.mu.Unlock()
+}
+
+// Header is the header byte: access mode and address.
+func (t *Transaction) Header() byte {
+	if len(t.MOSI) == 0 {
+		return 0
+	}
+	return t.MOSI[0]
+}
+
+// Status is the chip status byte returned for the header.
+func (t *Transaction) Status() StatusByte {
+	if len(t.MISO) == 0 {
+		return 0
+	}
+	return StatusByte(t.MISO[0])
+}
+
+// Op describes the access: "strobe", "read status", "read", "read burst",
+// "write" or "write burst".
+func (t *Transaction) Op() string {
+	h := t.Header()
+	addr := h & 0x3F
+	switch {
+	case addr >= SRES && addr <= SNOP && h&CC1101_READBURST != CC1101_READBURST:
+		return "strobe"
+	case addr >= SRES && addr <= SNOP:
+		return "read status"
+	case h&CC1101_READBURST == CC1101_READBURST:
+		return "read burst"
+	case h&CC1101_READSINGLE != 0:
+		return "read"
+	case h&CC1101_WRITEBURST != 0:
+		return "write burst"
+	}
+	return "write"
+}
+
+// Name is the datasheet name of the register, strobe or FIFO accessed.
+func (t *Transaction) Name() string {
+	return headerName(t.Header())
+}
+
+// Data is the payload of the transaction: the bytes read for a read, the
+// bytes written for a write, nothing for a strobe.
+func (t *Transaction) Data() []byte {
+	if len(t.MOSI) < 2 {
+		return nil
+	}
+	if t.Header()&CC1101_READSINGLE != 0 {
+		return t.MISO[1:]
+	}
+	return t.MOSI[1:]
+}
+
+func (t *Transaction) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-11s %-14s (0x%02X)", t.Op(), t.Name(), t.Header())
+	if data := t.Data(); len(data) > 0 {
+		fmt.Fprintf(&b, " % X", data)
+	}
+	fmt.Fprintf(&b, " [%s] %v", t.Status(), t.Duration)
+	if t.Err != nil {
+		fmt.Fprintf(&b, " error: %v", t.Err)
+	}
+	return b.String()
+}
+
+var strobeNames = [...]string{
+	"SRES", "SFSTXON", "SXOFF", "SCAL", "SRX", "STX", "SIDLE", "SAFC",
+	"SWOR", "SPWD", "SFRX", "SFTX", "SWORRST", "SNOP",
+}
+
+var statusNames = [...]string{
+	"PARTNUM", "VERSION", "FREQEST", "LQI", "RSSI", "MARCSTATE", "WORTIME1", "WORTIME0",
+	"PKTSTATUS", "VCO_VC_DAC", "TXBYTES", "RXBYTES", "RCCTRL1_STATUS", "RCCTRL0_STATUS",
+}
+
+// headerName names the register, strobe or FIFO addressed by a header byte.
+func headerName(h byte) string {
+	addr := h & 0x3F
+	switch {
+	case addr < CFG_REGISTER:
+		return RegisterName(addr)
+	case addr >= SRES && addr <= SNOP:
+		if h&CC1101_READBURST == CC1101_READBURST {
+			return statusNames[addr-SRES]
+		}
+		return strobeNames[addr-SRES]
+	case addr == PATABLE:
+		return "PATABLE"
+	case addr == TXFIFO_SINGLE_BYTE && h&CC1101_READSINGLE != 0:
+		return "RXFIFO"
+	case addr == TXFIFO_SINGLE_BYTE:
+		return "TXFIFO"
+	}
+	return fmt.Sprintf("0x%02X", addr)
+}
+
+// SlogTracer logs every transaction to a slog.Logger at debug level.
+type SlogTracer struct {
+	Logger *slog.Logger
+}
+
+func (s SlogTracer) Trace(t *Transaction) {
+	attrs := []slog.Attr{
+		slog.String("op", t.Op()),
+		slog.String("reg", t.Name()),
+		slog.String("header", fmt.Sprintf("0x%02X", t.Header())),
+		slog.String("data", fmt.Sprintf("% X", t.Data())),
+		slog.String("status", t.Status().String()),
+		slog.Duration("duration", t.Duration),
+	}
+	if t.Err != nil {
+		attrs = append(attrs, slog.String("error", t.Err.Error()))
+	}
+	s.Logger.LogAttrs(context.Background(), slog.LevelDebug, "cc1101 spi", attrs...)
+}
+
+// Recorder is a Tracer keeping the last transactions in a ring buffer, to
+// be compared with a trace taken from a known-good setup or replayed with
+// ReplaySPI.
+type Recorder struct {
+	mu   sync.Mutex
+	ring []Transaction
+	size int
+	next int
+	full bool
+}
+
+// NewRecorder returns a Recorder keeping the last n transactions, or all of
+// them when n is 0.
+func NewRecorder(n int) *Recorder {
+	return &Recorder{ring: make([]Transaction, n), size: n}
+}
+
+func (r *Recorder) Trace(t *Transaction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.size == 0 {
+		r.ring = append(r.ring, Transaction{})
+		r.next = len(r.ring) - 1
+	}
+	slot := &r.ring[r.next]
+	slot.Start, slot.Duration, slot.Err = t.Start, t.Duration, t.Err
+	slot.MOSI = append(slot.MOSI[:0], t.MOSI...)
+	slot.MISO = append(slot.MISO[:0], t.MISO...)
+	r.next++
+	if r.next == r.size {
+		r.next, r.full = 0, true
+	}
+}
+
+// Transactions returns a copy of the recorded transactions, oldest first.
+func (r *Recorder) Transactions() []Transaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Transaction
+	if r.full {
+		out = append(out, r.ring[r.next:]...)
+	}
+	out = append(out, r.ring[:r.next]...)
+	for i := range out {
+		out[i].MOSI = append([]byte(nil), out[i].MOSI...)
+		out[i].MISO = append([]byte(nil), out[i].MISO...)
+	}
+	return out
+}
+
+// Reset discards the recorded transactions.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	r.next, r.full = 0, false
+	if r.size == 0 {
+		r.ring = r.ring[:0]
+	}
+	r.mu.Unlock()
+}
+
+// String lists the recorded transactions, one per line.
+func (r *Recorder) String() string {
+	var b strings.Builder
+	for _, t := range r.Transactions() {
+		b.WriteString(t.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,101 @@
+package cc1101
+
+import "math"
+
+// Conversions between register fields and physical units, all derived from
+// the 26 MHz crystal. Formulas from section 12 to 21 of
+// https://www.ti.com/lit/ds/symlink/cc1101.pdf
+
+// Number of preamble bytes for each NUM_PREAMBLE setting.
+var preambleBytes = [8]int{2, 3, 4, 6, 8, 12, 16, 24}
+
+// FrequencyHz is the base frequency programmed in FREQ2/FREQ1/FREQ0.
+func FrequencyHz(freq2, freq1, freq0 byte) float64 {
+	word := uint32(freq2&0x3F)<<16 | uint32(freq1)<<8 | uint32(freq0)
+	return float64(word) * CRYSTAL_FREQUENCY / (1 << 16)
+}
+
+// DataRate is the symbol rate in baud for MDMCFG4 DRATE_E and MDMCFG3 DRATE_M.
+func DataRate(drateE, drateM byte) float64 {
+	return float64(256+uint32(drateM)) * float64(uint32(1)<<(drateE&0x0F)) * CRYSTAL_FREQUENCY / (1 << 28)
+}
+
+// RxBandwidth is the channel filter bandwidth in Hz for MDMCFG4 CHANBW_E/M.
+func RxBandwidth(chanbwE, chanbwM byte) float64 {
+	return CRYSTAL_FREQUENCY / (8 * float64(4+uint32(chanbwM&0x03)) * float64(uint32(1)<<(chanbwE&0x03)))
+}
+
+// Deviation is the FSK frequency deviation in Hz for DEVIATN.
+func Deviation(deviationE, deviationM byte) float64 {
+	return CRYSTAL_FREQUENCY / (1 << 17) * float64(8+uint32(deviationM&0x07)) * float64(uint32(1)<<(deviationE&0x07))
+}
+
+// ChannelSpacing is the channel spacing in Hz for MDMCFG1 CHANSPC_E and
+// MDMCFG0 CHANSPC_M.
+func ChannelSpacing(chanspcE, chanspcM byte) float64 {
+	return CRYSTAL_FREQUENCY / (1 << 18) * float64(256+uint32(chanspcM)) * float64(uint32(1)<<(chanspcE&0x03))
+}
+
+// IntermediateFrequency is the IF in Hz for FSCTRL1 FREQ_IF.
+func IntermediateFrequency(freqIF byte) float64 {
+	return CRYSTAL_FREQUENCY / (1 << 10) * float64(freqIF&0x1F)
+}
+
+// frequencyWord is the FREQ2/FREQ1/FREQ0 word closest to hz.
+func frequencyWord(hz float64) uint32 {
+	return uint32(math.Round(hz * (1 << 16) / CRYSTAL_FREQUENCY))
+}
+
+// dataRateSetting returns the DRATE_E/DRATE_M pair closest to baud.
+func dataRateSetting(baud float64) (drateE, drateM byte, ok bool) {
+	for e := 0; e < 16; e++ {
+		m := math.Round(baud*(1<<28)/(CRYSTAL_FREQUENCY*float64(uint32(1)<<e))) - 256
+		if m <= 255 {
+			if m < 0 {
+				return 0, 0, false
+			}
+			return byte(e), byte(m), true
+		}
+	}
+	return 0, 0, false
+}
+
+// Bandwidths within rxBandwidthTolerance Hz above a filter still select it,
+// so that a filter bandwidth rounded for display, such as 541.6667 kHz for
+// 541666.67 Hz, does not pick the next wider filter.
+const rxBandwidthTolerance = 1
+
+// rxBandwidthSetting returns the narrowest channel filter at least hz wide.
+func rxBandwidthSetting(hz float64) (chanbwE, chanbwM byte, ok bool) {
+	for e := 3; e >= 0; e-- {
+		for m := 3; m >= 0; m-- {
+			if RxBandwidth(byte(e), byte(m))+rxBandwidthTolerance >= hz {
+				return byte(e), byte(m), true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// deviationSetting returns the DEVIATION_E/DEVIATION_M pair closest to hz.
+func deviationSetting(hz float64) (deviationE, deviationM byte) {
+	best := math.Inf(1)
+	for e := byte(0); e < 8; e++ {
+		for m := byte(0); m < 8; m++ {
+			if diff := math.Abs(Deviation(e, m) - hz); diff < best {
+				best, deviationE, deviationM = diff, e, m
+			}
+		}
+	}
+	return deviationE, deviationM
+}
+
+// preambleSetting returns the smallest NUM_PREAMBLE sending at least n bytes.
+func preambleSetting(n int) (byte, bool) {
+	for i, bytes := range preambleBytes {
+		if bytes >= n {
+			return byte(i), true
+		}
+	}
+	return 0, false
+}
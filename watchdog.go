@@ -0,0 +1,189 @@
+package cc1101
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Brownout detection. After a brownout the chip comes back with its reset
+// defaults while the driver still believes it is configured. The watchdog
+// reads back a sentinel register and the whole configuration, compares
+// them with the shadow copy of what the driver wrote, and writes the shadow
+// back when they differ.
+
+// Bits the chip itself updates when calibrating the frequency synthesizer,
+// which the watchdog does not compare: FSCAL3[3:0], FSCAL2[4:0] and
+// FSCAL1[5:0].
+var volatileBits = [CFG_REGISTER]byte{
+	FSCAL3: 0x0F,
+	FSCAL2: 0x1F,
+	FSCAL1: 0x3F,
+}
+
+// Registers tried, in order, as the sentinel: the first whose shadow value
+// differs from its reset default. A reset is certain when it reads back as
+// the default.
+var sentinelCandidates = [...]byte{SYNC1, SYNC0, FREQ2, FREQ1, FREQ0, MDMCFG4, MDMCFG3, PKTCTRL0, IOCFG0, IOCFG2}
+
+// ReconfigEvent describes a configuration mismatch found by CheckConfig.
+type ReconfigEvent struct {
+	Time time.Time
+
+	// ChipReset is set when the sentinel register held its reset value:
+	// the chip was reset, rather than a register being corrupted.
+	ChipReset bool
+
+	// Register fields and PATABLE entries found different from the
+	// configuration, calibration results excepted.
+	Changes []Change
+
+	// Err is the error writing the configuration back, if any. The chip
+	// is left in IDLE after a successful reconfiguration.
+	Err error
+}
+
+func (e *ReconfigEvent) String() string {
+	what := "configuration mismatch"
+	if e.ChipReset {
+		what = "chip reset"
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("%s, %d changes, reconfiguration failed: %v", what, len(e.Changes), e.Err)
+	}
+	return fmt.Sprintf("%s, %d changes, reconfigured", what, len(e.Changes))
+}
+
+// ConfigChecksum is a CRC-16 of the configuration registers and PATABLE,
+// leaving out the calibration results.
+func ConfigChecksum(s *Snapshot) uint16 {
+	var buf [CFG_REGISTER + PATABLE_SIZE]byte
+	for addr, value := range s.Registers {
+		buf[addr] = value &^ volatileBits[addr]
+	}
+	copy(buf[CFG_REGISTER:], s.PATable[:])
+	return CRC16(buf[:])
+}
+
+// ErrConfigUnknown is returned by CheckConfig and Scrub before Reset,
+// Restore or LoadShadow. New starts the shadow from the reset defaults,
+// which a chip configured earlier does not hold: comparing with them would
+// put that chip back to its defaults.
+var ErrConfigUnknown = errors.New("chip configuration not known: call Reset, ApplyConfig or LoadShadow first")
+
+// shadowSnapshot returns the shadow copy of the configuration.
+func (d *Device) shadowSnapshot() Snapshot {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return Snapshot{Registers: d.regs, PATable: d.patable}
+}
+
+// knownShadow is shadowSnapshot failing with ErrConfigUnknown until the
+// shadow holds the whole chip configuration.
+func (d *Device) knownShadow() (Snapshot, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.shadowKnown {
+		return Snapshot{}, ErrConfigUnknown
+	}
+	return Snapshot{Registers: d.regs, PATable: d.patable}, nil
+}
+
+// sentinel returns the register checked first by CheckConfig, and false
+// when the whole configuration is the reset default.
+func sentinel(s *Snapshot) (byte, bool) {
+	for _, addr := range sentinelCandidates {
+		if s.Registers[addr] != resetDefaults[addr] {
+			return addr, true
+		}
+	}
+	for addr, value := range s.Registers {
+		if value&^volatileBits[addr] != resetDefaults[addr]&^volatileBits[addr] {
+			return byte(addr), true
+		}
+	}
+	return 0, false
+}
+
+// CheckConfig compares the chip configuration with the shadow copy of the
+// values written by the driver. On a mismatch it puts the chip in IDLE,
+// writes the whole configuration and PATABLE back, and returns an event
+// describing it; it returns nil when the configuration matches. The error
+// is for failures reading the chip, or ErrConfigUnknown until the driver
+// knows the configuration: call Reset, ApplyConfig, ApplyPreset, Restore or
+// LoadShadow first.
+//
+// CheckConfig waits for any SendData or ReceiveData call in progress.
+func (d *Device) CheckConfig() (*ReconfigEvent, error) {
+	d.txMu.Lock()
+	defer d.txMu.Unlock()
+	d.rxMu.Lock()
+	defer d.rxMu.Unlock()
+	d.cfgMu.Lock()
+	defer d.cfgMu.Unlock()
+
+	want, err := d.knownShadow()
+	if err != nil {
+		return nil, err
+	}
+	chipReset := false
+	if addr, ok := sentinel(&want); ok {
+		value, err := d.ReadSingleRegister(addr)
+		if err != nil {
+			return nil, err
+		}
+		chipReset = value&^volatileBits[addr] == resetDefaults[addr]&^volatileBits[addr]
+	}
+
+	var got Snapshot
+	if err := d.readSnapshot(&got); err != nil {
+		return nil, err
+	}
+	if !chipReset && ConfigChecksum(&got) == ConfigChecksum(&want) {
+		return nil, nil
+	}
+
+	for addr, mask := range volatileBits {
+		got.Registers[addr] = got.Registers[addr]&^mask | want.Registers[addr]&mask
+	}
+	event := &ReconfigEvent{
+		Time:      time.Now(),
+		ChipReset: chipReset,
+		Changes:   Diff(&want, &got),
+	}
+	if err := d.Restore(&want); err != nil {
+		event.Err = err
+	} else if err := d.SpiStrobe(SCAL); err != nil {
+		// The restored calibration results may not match the chip.
+		event.Err = err
+	}
+	return event, nil
+}
+
+// Watchdog calls CheckConfig every interval until ctx is done, passing each
+// reconfiguration to onEvent, which may be nil. Read errors are passed to
+// onError, which may be nil too; the watchdog keeps running after them.
+//
+//	go cc.Watchdog(ctx, 10*time.Second, func(e *cc1101.ReconfigEvent) {
+//		log.Println("cc1101:", e)
+//		cc.SetRx()
+//	}, nil)
+func (d *Device) Watchdog(ctx context.Context, interval time.Duration, onEvent func(*ReconfigEvent), onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		event, err := d.CheckConfig()
+		switch {
+		case err != nil && onError != nil:
+			onError(err)
+		case event != nil && onEvent != nil:
+			onEvent(event)
+		}
+	}
+}
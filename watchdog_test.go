@@ -0,0 +1,139 @@
+package cc1101
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// newConfiguredDevice returns a fake device reset and configured with
+// PresetGFSK868.
+func newConfiguredDevice(t *testing.T) (*Device, *fakeChip) {
+	t.Helper()
+	d, chip := newFakeDevice()
+	if err := d.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.ApplyConfig(&PresetGFSK868.Config); err != nil {
+		t.Fatal(err)
+	}
+	return d, chip
+}
+
+func TestCheckConfigIntact(t *testing.T) {
+	d, chip := newConfiguredDevice(t)
+	// Calibration results are the chip's own.
+	chip.regs[FSCAL1] ^= 0x15
+	rec := NewRecorder(0)
+	d.SetTracer(rec)
+
+	event, err := d.CheckConfig()
+	if err != nil || event != nil {
+		t.Fatalf("CheckConfig = %v, %v; want no event", event, err)
+	}
+	for _, tr := range rec.Transactions() {
+		if op := tr.Op(); op != "read" && op != "read burst" {
+			t.Errorf("unexpected %s", &tr)
+		}
+	}
+}
+
+// A chip reset behind the driver is detected by its sentinel register,
+// reported with the fields that changed, and reconfigured then calibrated.
+func TestCheckConfigChipReset(t *testing.T) {
+	d, chip := newConfiguredDevice(t)
+	want, wantPA := chip.regs, chip.patable
+	chip.mu.Lock()
+	chip.reset()
+	chip.mu.Unlock()
+	rec := NewRecorder(0)
+	d.SetTracer(rec)
+
+	event, err := d.CheckConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event == nil || !event.ChipReset || event.Err != nil {
+		t.Fatalf("CheckConfig event = %v, want a chip reset", event)
+	}
+	// The preset keeps the default sync word, so FREQ2 is the sentinel.
+	if tr := rec.Transactions(); tr[0].Op() != "read" || tr[0].Name() != "FREQ2" {
+		t.Errorf("first transaction %s, want the FREQ2 sentinel read", &tr[0])
+	}
+	changed := make(map[string]bool)
+	for _, c := range event.Changes {
+		changed[c.Register+"."+c.Field] = true
+	}
+	for _, name := range []string{"FREQ2.FREQ[21:16]", "FREQ1.FREQ1", "MDMCFG2.MOD_FORMAT", "PATABLE.PATABLE[0]"} {
+		if !changed[name] {
+			t.Errorf("Changes %v do not include %s", event.Changes, name)
+		}
+	}
+
+	if chip.regs != want || chip.patable != wantPA {
+		t.Errorf("registers after CheckConfig:\n% X\nwant\n% X", chip.regs, want)
+	}
+	// The restore is followed by SCAL, as the written calibration
+	// results may not match the chip.
+	var ops []string
+	for _, tr := range rec.Transactions() {
+		if op := tr.Op(); op != "read" && op != "read burst" {
+			ops = append(ops, tr.Name())
+		}
+	}
+	if len(ops) == 0 || ops[len(ops)-1] != "SCAL" || ops[len(ops)-2] != "PATABLE" {
+		t.Errorf("writes and strobes %v, want the restore then SCAL", ops)
+	}
+
+	if event, err := d.CheckConfig(); event != nil || err != nil {
+		t.Errorf("second CheckConfig = %v, %v; want no event", event, err)
+	}
+}
+
+// A corrupted register with the sentinel intact is a mismatch, not a
+// reset.
+func TestCheckConfigCorruption(t *testing.T) {
+	d, chip := newConfiguredDevice(t)
+	chip.regs[PKTLEN] ^= 0x01
+
+	event, err := d.CheckConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event == nil || event.ChipReset {
+		t.Fatalf("CheckConfig event = %v, want a mismatch without reset", event)
+	}
+	if len(event.Changes) != 1 || event.Changes[0].Addr != PKTLEN {
+		t.Errorf("Changes = %v, want PKTLEN only", event.Changes)
+	}
+}
+
+func TestWatchdog(t *testing.T) {
+	d, chip := newConfiguredDevice(t)
+	chip.mu.Lock()
+	chip.reset()
+	chip.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan *ReconfigEvent, 1)
+	done := make(chan struct{})
+	go func() {
+		d.Watchdog(ctx, time.Millisecond, func(e *ReconfigEvent) {
+			select {
+			case events <- e:
+			default:
+			}
+		}, func(err error) { t.Error(err) })
+		close(done)
+	}()
+	select {
+	case e := <-events:
+		if !e.ChipReset {
+			t.Errorf("event %v, want a chip reset", e)
+		}
+	case <-time.After(time.Second):
+		t.Error("no event from the watchdog")
+	}
+	cancel()
+	<-done
+}